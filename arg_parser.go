@@ -1,10 +1,40 @@
 package cli
 
+import (
+	"strings"
+
+	"github.com/bobg/errors"
+)
+
+var InvalidMapEntryError = errors.New("map entries must be of the form key=value")
+
 type argParser interface {
 	Type() any
 	Parse(string) (any, error)
 }
 
+// accumulatingArgParser is an argParser whose values are collected across repeated flags.
+type accumulatingArgParser interface {
+	argParser
+	accumulate(existing any, s string) (any, error)
+
+	// appendElement adds s to existing as a single element, without splitting it on commas.
+	appendElement(existing any, s string) (any, error)
+}
+
+// variadicArgParser is an argParser which can collect one value per token for a variadic argument.
+type variadicArgParser interface {
+	variadic() accumulatingArgParser
+}
+
+func parseValue(parser argParser, existing any, s string) (any, error) {
+	if parser, isAccumulating := parser.(accumulatingArgParser); isAccumulating {
+		return parser.accumulate(existing, s)
+	}
+
+	return parser.Parse(s)
+}
+
 // ArgParser is a function that parses a string into a value of type T.
 type ArgParser[T any] func(string) (T, error)
 
@@ -21,3 +51,121 @@ func (ArgParser[T]) Type() any {
 func (p ArgParser[T]) Parse(s string) (any, error) {
 	return p(s)
 }
+
+func (p ArgParser[T]) variadic() accumulatingArgParser {
+	return sliceArgParser[T]{element: p}
+}
+
+type sliceArgParser[T any] struct {
+	element   ArgParser[T]
+	separator string
+}
+
+func newSliceArgParser[T any](element ArgParser[T]) sliceArgParser[T] {
+	return sliceArgParser[T]{element: element, separator: ","}
+}
+
+func (sliceArgParser[T]) Type() any {
+	var t []T
+	return t
+}
+
+func (p sliceArgParser[T]) Parse(s string) (any, error) {
+	return p.accumulate(nil, s)
+}
+
+func (p sliceArgParser[T]) accumulate(existing any, s string) (any, error) {
+	values, _ := existing.([]T)
+	if values == nil {
+		values = []T{}
+	}
+
+	if s == "" {
+		return values, nil
+	}
+
+	rawElements := []string{s}
+	if p.separator != "" {
+		rawElements = strings.Split(s, p.separator)
+	}
+
+	var result any = values
+	for _, rawElement := range rawElements {
+		var err error
+		if result, err = p.appendElement(result, rawElement); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (p sliceArgParser[T]) appendElement(existing any, s string) (any, error) {
+	values, _ := existing.([]T)
+	element, err := p.element(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(values, element), nil
+}
+
+func (p sliceArgParser[T]) variadic() accumulatingArgParser {
+	return sliceArgParser[T]{element: p.element}
+}
+
+type mapArgParser[T any] struct {
+	value ArgParser[T]
+}
+
+func newMapArgParser[T any](value ArgParser[T]) mapArgParser[T] {
+	return mapArgParser[T]{value: value}
+}
+
+func (mapArgParser[T]) Type() any {
+	var t map[string]T
+	return t
+}
+
+func (p mapArgParser[T]) Parse(s string) (any, error) {
+	return p.accumulate(nil, s)
+}
+
+func (p mapArgParser[T]) accumulate(existing any, s string) (any, error) {
+	values, _ := existing.(map[string]T)
+	if values == nil {
+		values = make(map[string]T)
+	}
+
+	if s == "" {
+		return values, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		if _, err := p.appendElement(values, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (p mapArgParser[T]) appendElement(existing any, s string) (any, error) {
+	values, _ := existing.(map[string]T)
+	if values == nil {
+		values = make(map[string]T)
+	}
+
+	key, rawValue, found := strings.Cut(s, "=")
+	if !found {
+		return nil, errors.Wrapf(InvalidMapEntryError, "entry %q", s)
+	}
+
+	value, err := p.value(rawValue)
+	if err != nil {
+		return nil, err
+	}
+
+	values[key] = value
+	return values, nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sliceArgParser(t *testing.T) {
+	parser := newSliceArgParser(IntParser)
+	assert.Equal(t, []int(nil), parser.Type())
+
+	value, err := parser.Parse("1,2")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, value)
+
+	value, err = parser.accumulate(value, "3")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, value)
+
+	value, err = parser.Parse("")
+	require.NoError(t, err)
+	assert.Equal(t, []int{}, value)
+
+	_, err = parser.Parse("1,two")
+	assert.EqualError(t, err, `strconv.Atoi: parsing "two": invalid syntax`)
+}
+
+func Test_mapArgParser(t *testing.T) {
+	parser := newMapArgParser(StringParser)
+	assert.Equal(t, map[string]string(nil), parser.Type())
+
+	value, err := parser.Parse("env=prod,team=infra")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "infra"}, value)
+
+	value, err = parser.accumulate(value, "env=staging")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "staging", "team": "infra"}, value)
+
+	_, err = parser.Parse("env")
+	assert.ErrorIs(t, err, InvalidMapEntryError)
+}
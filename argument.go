@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/bobg/errors"
 	"github.com/broothie/option"
@@ -13,6 +15,13 @@ type Argument struct {
 	description  string
 	parser       argParser
 	defaultValue any
+	completer    Completer
+	choices      []string
+	choicesFunc  ChoicesFunc
+	isVariadic   bool
+	minCount     int
+	maxCount     int
+	deprecation  string
 
 	value any
 }
@@ -33,10 +42,18 @@ func newArgument(name, description string, options ...option.Option[*Argument])
 		return nil, errors.Wrapf(err, "invalid argument %q", name)
 	}
 
+	if parser, isVariadic := argument.parser.(variadicArgParser); isVariadic && argument.isVariadic {
+		argument.parser = parser.variadic()
+	}
+
 	return argument, nil
 }
 
 func (a *Argument) isRequired() bool {
+	if a.isVariadic {
+		return a.minCount > 0 && a.defaultValue == nil
+	}
+
 	return a.defaultValue == nil
 }
 
@@ -44,12 +61,61 @@ func (a *Argument) isOptional() bool {
 	return !a.isRequired()
 }
 
+// resetArguments clears the values parsed into the command's arguments, so variadic arguments start over on each
+// parse instead of building on the last one.
+func (c *Command) resetArguments() {
+	for _, argument := range c.arguments {
+		argument.value = nil
+	}
+}
+
+func (a *Argument) setValue(ctx context.Context, rawValue string) error {
+	value, err := parseValue(a.parser, a.value, rawValue)
+	if err != nil {
+		return err
+	}
+
+	if err := validateChoice(value, a.allowedChoices(ctx)); err != nil {
+		return err
+	}
+
+	a.value = value
+	return nil
+}
+
+func (a *Argument) valueOrDefault() any {
+	if a.value != nil {
+		return a.value
+	}
+
+	return a.defaultValue
+}
+
+func (a *Argument) valueCount() int {
+	if a.value == nil {
+		return 0
+	} else if !a.isVariadic {
+		return 1
+	}
+
+	return reflect.ValueOf(a.value).Len()
+}
+
+func (a *Argument) isFull() bool {
+	return a.isVariadic && a.maxCount > 0 && a.valueCount() >= a.maxCount
+}
+
 func (a *Argument) inBrackets() string {
+	name := fmt.Sprintf("<%s>", a.name)
+	if a.isVariadic {
+		name = fmt.Sprintf("%s...", name)
+	}
+
 	if a.isOptional() {
-		return fmt.Sprintf("[<%s>]", a.name)
+		return fmt.Sprintf("[%s]", name)
 	}
 
-	return fmt.Sprintf("<%s>", a.name)
+	return name
 }
 
 func (c *Command) findArg(name string) (*Argument, bool) {
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/bobg/errors"
@@ -15,5 +17,17 @@ func (a *Argument) validateConfig() error {
 		return errors.New("argument name cannot be empty")
 	}
 
+	if a.minCount < 0 {
+		return errors.Errorf("argument minimum count %d cannot be negative", a.minCount)
+	}
+
+	if a.maxCount > 0 && a.minCount > a.maxCount {
+		return errors.Errorf("argument minimum count %d cannot exceed maximum count %d", a.minCount, a.maxCount)
+	}
+
+	if a.isVariadic && a.defaultValue != nil && reflect.TypeOf(a.defaultValue).Kind() != reflect.Slice {
+		return errors.Errorf("variadic argument default %q must be a slice", fmt.Sprint(a.defaultValue))
+	}
+
 	return nil
 }
@@ -19,6 +19,30 @@ func TestArgument_validateConfig(t *testing.T) {
 		assert.EqualError(t, err, `argument name "invalid argument name" must be a single token`)
 	})
 
+	t.Run("negative minimum count", func(t *testing.T) {
+		arg := &Argument{name: "valid-arg", minCount: -1}
+		err := arg.validateConfig()
+		assert.EqualError(t, err, "argument minimum count -1 cannot be negative")
+	})
+
+	t.Run("minimum count exceeds maximum count", func(t *testing.T) {
+		arg := &Argument{name: "valid-arg", minCount: 3, maxCount: 2}
+		err := arg.validateConfig()
+		assert.EqualError(t, err, "argument minimum count 3 cannot exceed maximum count 2")
+	})
+
+	t.Run("variadic with non-slice default", func(t *testing.T) {
+		arg := &Argument{name: "valid-arg", isVariadic: true, defaultValue: "x"}
+		err := arg.validateConfig()
+		assert.EqualError(t, err, `variadic argument default "x" must be a slice`)
+	})
+
+	t.Run("variadic with slice default", func(t *testing.T) {
+		arg := &Argument{name: "valid-arg", isVariadic: true, defaultValue: []string{"x"}}
+		err := arg.validateConfig()
+		assert.NoError(t, err)
+	})
+
 	t.Run("valid name", func(t *testing.T) {
 		arg := &Argument{name: "valid-arg"}
 		err := arg.validateConfig()
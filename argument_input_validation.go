@@ -2,12 +2,19 @@ package cli
 
 import "github.com/bobg/errors"
 
-var ArgumentMissingValueError = errors.New("argument missing value")
+var (
+	ArgumentMissingValueError = errors.New("argument missing value")
+	TooFewArgumentsError      = errors.New("too few arguments")
+)
 
 func (a *Argument) validateInput() error {
 	if a.isRequired() && a.value == nil {
 		return errors.Wrapf(ArgumentMissingValueError, "argument %q", a.name)
 	}
 
+	if a.isVariadic && a.value != nil && a.valueCount() < a.minCount {
+		return errors.Wrapf(TooFewArgumentsError, "argument %q expects at least %d values, got %d", a.name, a.minCount, a.valueCount())
+	}
+
 	return nil
 }
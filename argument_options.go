@@ -23,3 +23,68 @@ func SetArgDefault[T Parseable](defaultValue T) option.Func[*Argument] {
 		return argument, nil
 	}
 }
+
+// SetArgVariadic makes the argument soak up the rest of the positional arguments, which are read with ArgValue as a []T.
+// A variadic argument must be the last argument, and requires at least one value unless it has a default.
+func SetArgVariadic() option.Func[*Argument] {
+	return SetArgVariadicCount(1, 0)
+}
+
+// SetArgVariadicCount makes the argument variadic, accepting between minCount and maxCount values.
+// A maxCount of 0 means there is no maximum.
+func SetArgVariadicCount(minCount, maxCount int) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.isVariadic = true
+		argument.minCount = minCount
+		argument.maxCount = maxCount
+		return argument, nil
+	}
+}
+
+// SetArgCompleter sets the function used to complete values of the argument.
+func SetArgCompleter(completer Completer) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.completer = completer
+		return argument, nil
+	}
+}
+
+// SetArgChoices restricts the values of the argument to the given choices.
+func SetArgChoices(choices ...string) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.choices = append(argument.choices, choices...)
+		return argument, nil
+	}
+}
+
+// SetArgChoicesFunc restricts the values of the argument to those returned by choicesFunc, evaluated when the argument
+// is parsed.
+func SetArgChoicesFunc(choicesFunc ChoicesFunc) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.choicesFunc = choicesFunc
+		return argument, nil
+	}
+}
+
+func setArgParser(parser argParser) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.parser = parser
+		return argument, nil
+	}
+}
+
+func setArgDefaultValue(defaultValue any) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.defaultValue = defaultValue
+		return argument, nil
+	}
+}
+
+// SetArgDeprecated makes passing the argument print a warning with deprecation to stderr. Unlike flags, deprecated
+// arguments stay in help, since hiding them would misstate the positions of the arguments after them.
+func SetArgDeprecated(deprecation string) option.Func[*Argument] {
+	return func(argument *Argument) (*Argument, error) {
+		argument.deprecation = deprecation
+		return argument, nil
+	}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/broothie/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgument_variadic(t *testing.T) {
+	newCommand := func(t *testing.T, handler Handler, options ...option.Option[*Argument]) *Command {
+		command, err := NewCommand("rm", "remove files",
+			AddFlag("force", "force", AddFlagShort('f'), SetFlagDefault(false)),
+			AddArg("mode", "mode"),
+			AddArg("file", "file to remove", append([]option.Option[*Argument]{SetArgParser(IntParser)}, options...)...),
+			SetHandler(handler),
+		)
+
+		require.NoError(t, err)
+		return command
+	}
+
+	t.Run("collects remaining arguments", func(t *testing.T) {
+		command := newCommand(t, func(ctx context.Context) error {
+			mode, err := ArgValue[string](ctx, "mode")
+			assert.NoError(t, err)
+			assert.Equal(t, "fast", mode)
+
+			files, err := ArgValue[[]int](ctx, "file")
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1, 2, 3}, files)
+
+			force, err := FlagValue[bool](ctx, "force")
+			assert.NoError(t, err)
+			assert.True(t, force)
+
+			return nil
+		}, SetArgVariadic())
+
+		assert.NoError(t, command.Run(context.TODO(), []string{"fast", "1", "-f", "2", "3"}))
+	})
+
+	t.Run("requires a value by default", func(t *testing.T) {
+		command := newCommand(t, func(ctx context.Context) error { return nil }, SetArgVariadic())
+
+		err := command.Run(context.TODO(), []string{"fast"})
+		assert.ErrorIs(t, err, ArgumentMissingValueError)
+	})
+
+	t.Run("minimum count", func(t *testing.T) {
+		command := newCommand(t, func(ctx context.Context) error { return nil }, SetArgVariadicCount(2, 0))
+
+		err := command.Run(context.TODO(), []string{"fast", "1"})
+		assert.EqualError(t, err, `argument "file" expects at least 2 values, got 1: too few arguments`)
+	})
+
+	t.Run("maximum count", func(t *testing.T) {
+		command := newCommand(t, func(ctx context.Context) error { return nil }, SetArgVariadicCount(1, 2))
+
+		err := command.Run(context.TODO(), []string{"fast", "1", "2", "3"})
+		assert.EqualError(t, err, `argument "file" accepts at most 2 values: too many arguments`)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		called := ensureCalled(t)
+		command := newCommand(t, func(ctx context.Context) error {
+			called()
+
+			files, err := ArgValue[[]int](ctx, "file")
+			assert.NoError(t, err)
+			assert.Empty(t, files)
+
+			return nil
+		}, SetArgVariadicCount(0, 0))
+
+		assert.NoError(t, command.Run(context.TODO(), []string{"fast"}))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		command, err := NewCommand("ls", "list files",
+			AddArg("path", "paths to list", SetArgDefault([]string{"."}), SetArgVariadic()),
+			SetHandler(func(ctx context.Context) error {
+				paths, err := ArgValue[[]string](ctx, "path")
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"."}, paths)
+
+				return nil
+			}),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), nil))
+	})
+
+	t.Run("commas are not split", func(t *testing.T) {
+		command, err := NewCommand("ls", "list files",
+			AddArg("path", "paths to list", SetArgDefault([]string{"."}), SetArgVariadic()),
+			SetHandler(func(ctx context.Context) error {
+				paths, err := ArgValue[[]string](ctx, "path")
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"a,b", "c"}, paths)
+
+				return nil
+			}),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), []string{"a,b", "c"}))
+	})
+
+	t.Run("each run starts over", func(t *testing.T) {
+		var files []string
+		command, err := NewCommand("app", "an app",
+			AddArg("files", "files", SetArgVariadicCount(1, 3)),
+			SetHandler(func(ctx context.Context) error {
+				var err error
+				files, err = ArgValue[[]string](ctx, "files")
+				return err
+			}),
+		)
+
+		require.NoError(t, err)
+
+		require.NoError(t, command.Run(context.TODO(), []string{"a", "b", "c"}))
+		assert.Equal(t, []string{"a", "b", "c"}, files)
+
+		require.NoError(t, command.Run(context.TODO(), []string{"d"}))
+		assert.Equal(t, []string{"d"}, files)
+	})
+}
+
+func TestArgument_inBrackets(t *testing.T) {
+	testCases := map[string]struct {
+		options  []option.Option[*Argument]
+		expected string
+	}{
+		"required":          {expected: "<file>"},
+		"optional":          {options: []option.Option[*Argument]{SetArgDefault("a")}, expected: "[<file>]"},
+		"variadic":          {options: []option.Option[*Argument]{SetArgVariadic()}, expected: "<file>..."},
+		"optional variadic": {options: []option.Option[*Argument]{SetArgVariadicCount(0, 0)}, expected: "[<file>...]"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			argument, err := newArgument("file", "file", testCase.options...)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, argument.inBrackets())
+		})
+	}
+}
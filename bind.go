@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/bobg/errors"
+)
+
+const bindTag = "cli"
+
+var (
+	InvalidBindTargetError = errors.New("invalid bind target")
+	BindNameNotFoundError  = errors.New("no flag or argument found")
+	BindTypeMismatchError  = errors.New("bind type mismatch")
+)
+
+// Bind returns a T with each field tagged `cli:"name"` set to the value of the flag or argument with that name.
+// Flags take precedence over arguments of the same name. Fields without the tag are left untouched.
+func Bind[T any](ctx context.Context) (T, error) {
+	var target T
+
+	command, err := commandFromContext(ctx)
+	if err != nil {
+		return target, errors.Wrap(err, "binding")
+	}
+
+	if err := command.bind(ctx, reflect.ValueOf(&target).Elem()); err != nil {
+		return target, err
+	}
+
+	return target, nil
+}
+
+func (c *Command) bind(ctx context.Context, target reflect.Value) error {
+	if target.Kind() != reflect.Struct {
+		return errors.Wrapf(InvalidBindTargetError, "type %s is not a struct", target.Type())
+	}
+
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		name, found := field.Tag.Lookup(bindTag)
+		if !found || name == "" || name == "-" || isSubCommandType(field.Type) {
+			continue
+		}
+
+		if !field.IsExported() {
+			return errors.Wrapf(InvalidBindTargetError, "field %s is not exported", field.Name)
+		}
+
+		value, err := c.bindValue(ctx, name)
+		if err != nil {
+			return errors.Wrapf(err, "binding field %s", field.Name)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		reflectValue := reflect.ValueOf(value)
+		if !reflectValue.Type().AssignableTo(field.Type) {
+			return errors.Wrapf(BindTypeMismatchError, "field %s of type %s cannot hold %q of type %s", field.Name, field.Type, name, reflectValue.Type())
+		}
+
+		target.Field(i).Set(reflectValue)
+	}
+
+	return nil
+}
+
+func (c *Command) bindValue(ctx context.Context, name string) (any, error) {
+	if flag, found := c.findFlag(name); found {
+		return c.flagValue(ctx, flag)
+	}
+
+	if argument, found := c.findArg(name); found {
+		return argument.valueOrDefault(), nil
+	}
+
+	return nil, errors.Wrapf(BindNameNotFoundError, "name %q", name)
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind(t *testing.T) {
+	type Input struct {
+		Port     int           `cli:"port"`
+		Verbose  int           `cli:"verbose"`
+		Timeout  time.Duration `cli:"timeout"`
+		Tags     []string      `cli:"tag"`
+		Target   string        `cli:"target"`
+		Paths    []string      `cli:"paths"`
+		Untagged string
+		Ignored  string `cli:"-"`
+	}
+
+	called := ensureCalled(t)
+	command, err := NewCommand("server", "An http server.",
+		AddFlag("port", "Port", SetFlagDefault(3000), SetFlagIsInherited(true)),
+		AddFlag("verbose", "Verbosity", AddFlagShort('v'), SetFlagCounter(), SetFlagIsInherited(true)),
+		AddSubCmd("proxy", "Proxy requests",
+			AddFlag("timeout", "Timeout", SetFlagDefault(time.Second)),
+			AddFlag("tag", "Tag", SetFlagDefault([]string{})),
+			AddArg("target", "Target"),
+			AddArg("paths", "Paths", SetArgVariadicCount(0, 0)),
+			SetHandlerWith(func(ctx context.Context, input Input) error {
+				called()
+
+				assert.Equal(t,
+					Input{
+						Port:    3000,
+						Verbose: 2,
+						Timeout: 5 * time.Second,
+						Tags:    []string{"a", "b"},
+						Target:  "example.com",
+						Paths:   []string{"/a", "/b"},
+					},
+					input,
+				)
+
+				return nil
+			}),
+		),
+	)
+
+	require.NoError(t, err)
+	require.NoError(t, command.Run(context.TODO(), []string{"-vv", "proxy", "--timeout", "5s", "--tag", "a", "--tag", "b", "example.com", "/a", "/b"}))
+}
+
+func TestBind_errors(t *testing.T) {
+	type TestCase struct {
+		bind          func(ctx context.Context) error
+		expectedError string
+	}
+
+	testCases := map[string]TestCase{
+		"not a struct": {
+			bind: func(ctx context.Context) error {
+				_, err := Bind[string](ctx)
+				return err
+			},
+			expectedError: "type string is not a struct: invalid bind target",
+		},
+		"unknown name": {
+			bind: func(ctx context.Context) error {
+				_, err := Bind[struct {
+					Host string `cli:"host"`
+				}](ctx)
+				return err
+			},
+			expectedError: `binding field Host: name "host": no flag or argument found`,
+		},
+		"type mismatch": {
+			bind: func(ctx context.Context) error {
+				_, err := Bind[struct {
+					Port string `cli:"port"`
+				}](ctx)
+				return err
+			},
+			expectedError: `field Port of type string cannot hold "port" of type int: bind type mismatch`,
+		},
+		"unexported field": {
+			bind: func(ctx context.Context) error {
+				_, err := Bind[struct {
+					port int `cli:"port"`
+				}](ctx)
+				return err
+			},
+			expectedError: "field port is not exported: invalid bind target",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, err := NewCommand("server", "An http server.",
+				AddFlag("port", "Port", SetFlagDefault(3000)),
+				SetHandler(testCase.bind),
+			)
+
+			require.NoError(t, err)
+			assert.EqualError(t, command.Run(context.TODO(), nil), testCase.expectedError)
+		})
+	}
+
+	t.Run("not a command context", func(t *testing.T) {
+		_, err := Bind[struct{}](context.TODO())
+		assert.ErrorIs(t, err, NotACommandContextError)
+	})
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+var InvalidChoiceError = errors.New("invalid choice")
+
+// ChoicesFunc returns the allowed values of a flag or argument. It is evaluated at parse time.
+type ChoicesFunc func(ctx context.Context) []string
+
+func (f *Flag) allowedChoices(ctx context.Context) []string {
+	return allowedChoices(ctx, f.choices, f.choicesFunc)
+}
+
+func (a *Argument) allowedChoices(ctx context.Context) []string {
+	return allowedChoices(ctx, a.choices, a.choicesFunc)
+}
+
+func allowedChoices(ctx context.Context, choices []string, choicesFunc ChoicesFunc) []string {
+	if choicesFunc == nil {
+		return choices
+	}
+
+	return append(append([]string(nil), choices...), choicesFunc(ctx)...)
+}
+
+func validateChoice(value any, choices []string) error {
+	if len(choices) == 0 {
+		return nil
+	}
+
+	for _, element := range choiceElements(value) {
+		if lo.Contains(choices, element) {
+			continue
+		}
+
+		return errors.Wrapf(InvalidChoiceError, "%q is not one of %s%s", element, strings.Join(choices, "|"), didYouMean(element, choices))
+	}
+
+	return nil
+}
+
+func choiceElements(value any) []string {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice {
+		return []string{fmt.Sprint(value)}
+	}
+
+	elements := make([]string, reflectValue.Len())
+	for i := range elements {
+		elements[i] = fmt.Sprint(reflectValue.Index(i).Interface())
+	}
+
+	return elements
+}
+
+func choicesCompletions(choices []string, partial string) []Completion {
+	return lo.FilterMap(choices, func(choice string, _ int) (Completion, bool) {
+		return Completion{Value: choice}, strings.HasPrefix(choice, partial)
+	})
+}
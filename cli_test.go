@@ -180,6 +180,63 @@ func Test_git(t *testing.T) {
 				}
 			},
 		},
+		"terminator passes flag-like values to arguments": {
+			rawArgs: []string{"checkout", "--", "-b"},
+			checkoutHandler: func(t *testing.T) Handler {
+				called := ensureCalled(t)
+				return func(ctx context.Context) error {
+					called()
+
+					branch, err := ArgValue[string](ctx, "branch")
+					assert.NoError(t, err)
+					assert.Equal(t, "-b", branch)
+
+					isNewBranch, err := FlagValue[bool](ctx, "new-branch")
+					assert.NoError(t, err)
+					assert.False(t, isNewBranch)
+
+					restArgs, err := RestArgs(ctx)
+					assert.NoError(t, err)
+					assert.Empty(t, restArgs)
+
+					return nil
+				}
+			},
+		},
+		"terminator collects rest args": {
+			rawArgs: []string{"checkout", "some-branch", "--", "--force", "commit", "--"},
+			checkoutHandler: func(t *testing.T) Handler {
+				called := ensureCalled(t)
+				return func(ctx context.Context) error {
+					called()
+
+					branch, err := ArgValue[string](ctx, "branch")
+					assert.NoError(t, err)
+					assert.Equal(t, "some-branch", branch)
+
+					restArgs, err := RestArgs(ctx)
+					assert.NoError(t, err)
+					assert.Equal(t, []string{"--force", "commit", "--"}, restArgs)
+
+					return nil
+				}
+			},
+		},
+		"terminator stops sub-command dispatch": {
+			rawArgs: []string{"--git-dir", "/path", "--", "commit", "-m"},
+			gitHandler: func(t *testing.T) Handler {
+				called := ensureCalled(t)
+				return func(ctx context.Context) error {
+					called()
+
+					restArgs, err := RestArgs(ctx)
+					assert.NoError(t, err)
+					assert.Equal(t, []string{"commit", "-m"}, restArgs)
+
+					return nil
+				}
+			},
+		},
 		"env var based flag is evaluated": {
 			gitHandler: func(t *testing.T) Handler {
 				called := ensureCalled(t)
@@ -8,20 +8,39 @@ import (
 
 	"github.com/bobg/errors"
 	"github.com/broothie/option"
+	"github.com/samber/lo"
 )
 
 type Handler func(ctx context.Context) error
 
 type Command struct {
-	name        string
-	description string
-	version     string
-	aliases     []string
-	parent      *Command
-	subCommands []*Command
-	flags       []*Flag
-	arguments   []*Argument
-	handler     Handler
+	name            string
+	description     string
+	longDescription string
+	epilog          string
+	examples        []Example
+	deprecation     string
+	replacedBy      string
+	isHidden        bool
+	version         string
+	aliases         []string
+	parent          *Command
+	subCommands     []*Command
+	flags           []*Flag
+	arguments       []*Argument
+	handler         Handler
+
+	flagConstraints []flagConstraint
+	helpRenderer    HelpRenderer
+	theme           *Theme
+	helpWidth       *int
+	helpSortOrder   *HelpSortOrder
+	group           string
+
+	isPrefixMatching  bool
+	completionEnabled bool
+	config            map[string]any
+	restArgs          []string
 }
 
 // NewCommand creates a new command.
@@ -56,8 +75,19 @@ func Run(name, description string, options ...option.Option[*Command]) {
 	}
 }
 
-// Run runs the command.
+// Run runs the command. Run on a root command first checks the flag constraints and replacements of the whole tree,
+// which can only be checked once every sub-command is mounted.
 func (c *Command) Run(ctx context.Context, rawArgs []string) error {
+	if c.isCompleteRequest(rawArgs) {
+		return c.writeCompletions(ctx, os.Stdout, rawArgs[1:])
+	}
+
+	if c.isRoot() {
+		if err := c.validateTree(); err != nil {
+			return err
+		}
+	}
+
 	if commandProcessed, err := c.newParser(rawArgs).parse(ctx); err != nil {
 		return err
 	} else if commandProcessed {
@@ -69,13 +99,21 @@ func (c *Command) Run(ctx context.Context, rawArgs []string) error {
 
 func (c *Command) runHandler(ctx context.Context) error {
 	if c.isHelpFlagAsserted() {
+		if c.isJSONHelpRequested() {
+			return c.writeSchema(os.Stdout)
+		}
+
 		return c.renderHelp(os.Stdout)
 	} else if c.isVersionFlagAsserted() {
 		fmt.Println(c.findVersion())
 		return nil
 	}
 
-	if err := c.validateInput(); err != nil {
+	if err := c.loadConfig(ctx); err != nil {
+		return err
+	}
+
+	if err := c.validateInput(ctx); err != nil {
 		return err
 	}
 
@@ -92,6 +130,12 @@ func (c *Command) isHelpFlagAsserted() bool {
 	return found && flag.isBool() && flag.value != nil && flag.value.(bool)
 }
 
+func (c *Command) isJSONHelpRequested() bool {
+	flag, found := c.findFlagUpToRoot(func(flag *Flag) bool { return flag.isHelp })
+
+	return found && flag.helpFormat == helpFormatJSON
+}
+
 func (c *Command) isVersionFlagAsserted() bool {
 	flag, found := c.findFlagUpToRoot(func(flag *Flag) bool { return flag.isVersion })
 
@@ -122,6 +166,25 @@ func (c *Command) qualifiedName() string {
 	return c.name
 }
 
+func (c *Command) names() []string {
+	return append([]string{c.name}, c.aliases...)
+}
+
+func (c *Command) hasName(name string) bool {
+	return lo.Contains(c.names(), name)
+}
+
+func (c *Command) isPrefixMatchingEnabled() bool {
+	return c.isPrefixMatching || (c.hasParent() && c.parent.isPrefixMatchingEnabled())
+}
+
+func (c *Command) walk(visit func(*Command)) {
+	visit(c)
+	for _, subCommand := range c.subCommands {
+		subCommand.walk(visit)
+	}
+}
+
 func (c *Command) findVersion() string {
 	if c.version == "" && c.hasParent() {
 		return c.parent.findVersion()
@@ -8,6 +8,8 @@ func (c *Command) validateConfig() error {
 		c.validateNoDuplicateArguments,
 		c.validateNoDuplicateSubCommands,
 		c.validateEitherCommandsOrArguments,
+		c.validateVariadicArgumentIsLast,
+		c.validateSubCommandReplacements,
 	}
 
 	var errs []error
@@ -23,7 +25,7 @@ func (c *Command) validateNoDuplicateFlags() error {
 
 	var errs []error
 	for _, flag := range c.flags {
-		for _, name := range append([]string{flag.name}, flag.aliases...) {
+		for _, name := range flag.longNames() {
 			if flags[name] {
 				errs = append(errs, errors.Errorf("duplicate flag %q", name))
 			}
@@ -55,11 +57,13 @@ func (c *Command) validateNoDuplicateSubCommands() error {
 
 	var errs []error
 	for _, command := range c.subCommands {
-		if commands[command.name] {
-			errs = append(errs, errors.Errorf("duplicate sub-command %q", command.name))
-		}
+		for _, name := range command.names() {
+			if commands[name] {
+				errs = append(errs, errors.Errorf("duplicate sub-command %q", name))
+			}
 
-		commands[command.name] = true
+			commands[name] = true
+		}
 	}
 
 	return errors.Join(errs...)
@@ -72,3 +76,41 @@ func (c *Command) validateEitherCommandsOrArguments() error {
 
 	return nil
 }
+
+func (c *Command) validateVariadicArgumentIsLast() error {
+	var errs []error
+	for i, argument := range c.arguments {
+		if argument.isVariadic && i != len(c.arguments)-1 {
+			errs = append(errs, errors.Errorf("variadic argument %q must be the last argument", argument.name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTree checks the parts of the config of every command in the tree that depend on its parents, such as
+// inherited flags. It can only be run on a root once the tree is complete.
+func (c *Command) validateTree() error {
+	if err := errors.Join(c.validateFlagConstraints(), c.validateFlagReplacements()); err != nil {
+		return errors.Wrapf(err, "invalid command %q", c.name)
+	}
+
+	return nil
+}
+
+// validateFlagConstraints checks that the flag constraints of every command in the tree refer to flags the command can
+// see, including inherited ones.
+func (c *Command) validateFlagConstraints() error {
+	var errs []error
+	c.walk(func(command *Command) {
+		for _, constraint := range command.flagConstraints {
+			for _, name := range constraint.flagNames() {
+				if _, found := command.findFlag(name); !found {
+					errs = append(errs, errors.Errorf("flag constraint of %q refers to unknown flag %q", command.qualifiedName(), name))
+				}
+			}
+		}
+	})
+
+	return errors.Join(errs...)
+}
@@ -21,6 +21,13 @@ func TestCommand_config_validations(t *testing.T) {
 			),
 			expectedError: `invalid command "test": duplicate flag "some-flag"`,
 		},
+		"validateNoDuplicateFlags with negation": {
+			commandOptions: option.NewOptions(
+				AddFlag("color", "color", SetFlagDefault(true), SetFlagNegatable(true)),
+				AddFlag("no-color", "no color", SetFlagDefault(false)),
+			),
+			expectedError: `invalid command "test": duplicate flag "no-color"`,
+		},
 		"validateNoDuplicateArguments": {
 			commandOptions: option.NewOptions(
 				AddArg("some-arg", "some arg"),
@@ -35,6 +42,13 @@ func TestCommand_config_validations(t *testing.T) {
 			),
 			expectedError: `invalid command "test": duplicate sub-command "some-sub-command"`,
 		},
+		"validateNoDuplicateSubCommands with alias": {
+			commandOptions: option.NewOptions(
+				AddSubCmd("status", "status", AddAlias("st")),
+				AddSubCmd("stash", "stash", AddAlias("st")),
+			),
+			expectedError: `invalid command "test": duplicate sub-command "st"`,
+		},
 		"validateEitherCommandsOrArguments": {
 			commandOptions: option.NewOptions(
 				AddArg("some-arg", "some arg"),
@@ -42,6 +56,19 @@ func TestCommand_config_validations(t *testing.T) {
 			),
 			expectedError: `invalid command "test": cannot have both sub-commands and arguments`,
 		},
+		"validateVariadicArgumentIsLast": {
+			commandOptions: option.NewOptions(
+				AddArg("some-arg", "some arg", SetArgVariadicCount(0, 0)),
+				AddArg("another-arg", "another arg", SetArgDefault("")),
+			),
+			expectedError: `invalid command "test": variadic argument "some-arg" must be the last argument`,
+		},
+		"validateSubCommandReplacements": {
+			commandOptions: option.NewOptions(
+				AddSubCmd("rm", "remove", SetCommandReplacedBy("remove")),
+			),
+			expectedError: `invalid command "test": sub-command "rm" replaced by unknown sub-command "remove"`,
+		},
 	}
 
 	for name, testCase := range testCases {
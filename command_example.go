@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+var InvalidExampleError = errors.New("invalid example")
+
+// Example is a sample invocation of a command, shown in help, man pages and markdown docs.
+type Example struct {
+	CommandLine string `json:"command_line"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// ValidateExamples dry-runs the examples of every command in the tree through the parser, without calling handlers.
+// It isn't done by Run, so call it from a test or before generating docs to catch examples that have gone stale.
+func (c *Command) ValidateExamples() error {
+	root := c.root()
+
+	var errs []error
+	root.walk(func(command *Command) {
+		for _, example := range command.examples {
+			if err := command.validateExample(example); err != nil {
+				errs = append(errs, errors.Wrapf(err, "example %q of %q", example.CommandLine, command.qualifiedName()))
+			}
+		}
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return errors.Wrapf(err, "invalid command %q", root.name)
+	}
+
+	return nil
+}
+
+func (c *Command) validateExample(example Example) error {
+	tokens, err := splitCommandLine(example.CommandLine)
+	if err != nil {
+		return err
+	}
+
+	root := c.root()
+	if len(tokens) == 0 || tokens[0] != root.name {
+		return errors.Wrapf(InvalidExampleError, "must start with %q", root.name)
+	}
+
+	reached, err := root.dryRun(context.Background(), tokens[1:])
+	if err != nil {
+		return err
+	}
+
+	if reached != c {
+		return errors.Wrapf(InvalidExampleError, "runs %q", reached.qualifiedName())
+	}
+
+	return nil
+}
+
+// dryRun parses tokens as Run would, returning the command whose handler would be called. Parsed values are reset
+// before and after, so neither a previous run nor the dry run leaks into the other.
+func (c *Command) dryRun(ctx context.Context, tokens []string) (*Command, error) {
+	c.walk((*Command).resetInput)
+	defer c.walk((*Command).resetInput)
+
+	reached := c
+	var run func(*Command, context.Context, []string) error
+	run = func(command *Command, ctx context.Context, tokens []string) error {
+		reached = command
+
+		parser := command.newParser(tokens)
+		parser.runSubCommand = run
+		parser.warnings = io.Discard
+		_, err := parser.parse(ctx)
+		return err
+	}
+
+	return reached, run(c, ctx, tokens)
+}
+
+func (c *Command) resetInput() {
+	c.resetFlags()
+	c.resetArguments()
+	c.restArgs = nil
+}
+
+// splitCommandLine splits a command line into tokens the way a POSIX shell would, honoring quotes and backslashes.
+func splitCommandLine(commandLine string) ([]string, error) {
+	var (
+		tokens    []string
+		current   strings.Builder
+		inToken   bool
+		quote     rune
+		isEscaped bool
+	)
+
+	for _, r := range commandLine {
+		switch {
+		case isEscaped:
+			current.WriteRune(r)
+			isEscaped = false
+
+		case r == '\\' && quote != '\'':
+			isEscaped, inToken = true, true
+
+		case quote != 0 && r == quote:
+			quote = 0
+
+		case quote != 0:
+			current.WriteRune(r)
+
+		case r == '"' || r == '\'':
+			quote, inToken = r, true
+
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 || isEscaped {
+		return nil, errors.Wrap(InvalidExampleError, "unterminated quote or escape")
+	}
+
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
+
+func (h HelpData) Examples() []Example {
+	return h.command.examples
+}
+
+func (h HelpData) LongDescription() string {
+	return h.command.longDescription
+}
+
+func (h HelpData) Epilog() string {
+	return h.command.epilog
+}
+
+// ExampleList is the examples indented for help, each preceded by its explanation as a comment.
+func (h HelpData) ExampleList() string {
+	return strings.Join(lo.Map(h.Examples(), func(example Example, _ int) string {
+		lines := []string{"  " + example.CommandLine}
+		if example.Explanation != "" {
+			lines = append([]string{"  # " + example.Explanation}, lines...)
+		}
+
+		return strings.Join(lines, "\n") + "\n"
+	}), "\n")
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/broothie/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_splitCommandLine(t *testing.T) {
+	testCases := map[string]struct {
+		commandLine    string
+		expectedTokens []string
+		expectedError  string
+	}{
+		"plain":          {commandLine: "git  commit -m msg", expectedTokens: []string{"git", "commit", "-m", "msg"}},
+		"double quotes":  {commandLine: `git commit -m "fix the \"bug\""`, expectedTokens: []string{"git", "commit", "-m", `fix the "bug"`}},
+		"single quotes":  {commandLine: `echo 'a \ b'`, expectedTokens: []string{"echo", `a \ b`}},
+		"escaped space":  {commandLine: `ls my\ file`, expectedTokens: []string{"ls", "my file"}},
+		"empty quotes":   {commandLine: `git commit -m ""`, expectedTokens: []string{"git", "commit", "-m", ""}},
+		"unterminated":   {commandLine: `git commit -m "oops`, expectedError: "unterminated quote or escape: invalid example"},
+		"trailing slash": {commandLine: `ls \`, expectedError: "unterminated quote or escape: invalid example"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tokens, err := splitCommandLine(testCase.commandLine)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedTokens, tokens)
+		})
+	}
+}
+
+func TestCommand_ValidateExamples(t *testing.T) {
+	type TestCase struct {
+		commandOptions option.Options[*Command]
+		expectedError  string
+	}
+
+	newOptions := func(example string) option.Options[*Command] {
+		return option.NewOptions(
+			AddFlag("verbose", "verbose", SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddSubCmd("remote", "manage remotes",
+				AddSubCmd("add", "add a remote",
+					AddExample(example, "Add a remote"),
+					AddFlag("fetch", "fetch", SetFlagDefault(false)),
+					AddArg("name", "name"),
+					AddArg("url", "url"),
+				),
+			),
+		)
+	}
+
+	testCases := map[string]TestCase{
+		"valid": {
+			commandOptions: newOptions("git remote add --verbose --fetch origin 'https://example.com/repo.git'"),
+		},
+		"renamed flag": {
+			commandOptions: newOptions("git remote add --fech origin url"),
+			expectedError:  `invalid command "git": example "git remote add --fech origin url" of "git remote add": no flag found for "--fech" (did you mean "--fetch"?): invalid flag`,
+		},
+		"too many arguments": {
+			commandOptions: newOptions("git remote add origin url extra"),
+			expectedError:  `invalid command "git": example "git remote add origin url extra" of "git remote add": only expected 2 arguments: too many arguments`,
+		},
+		"wrong root": {
+			commandOptions: newOptions("got remote add origin url"),
+			expectedError:  `invalid command "git": example "got remote add origin url" of "git remote add": must start with "git": invalid example`,
+		},
+		"runs another command": {
+			commandOptions: newOptions("git remote"),
+			expectedError:  `invalid command "git": example "git remote" of "git remote add": runs "git remote": invalid example`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, err := NewCommand("git", "git", testCase.commandOptions...)
+			require.NoError(t, err)
+
+			err = command.ValidateExamples()
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			add := command.subCommands[0].subCommands[0]
+			assert.Nil(t, command.flags[0].value)
+			assert.Nil(t, add.flags[0].value)
+			assert.Nil(t, add.arguments[0].value)
+		})
+	}
+
+	t.Run("handler is not called", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddExample("test", ""),
+			SetHandler(func(context.Context) error {
+				t.Fatal("handler called")
+				return nil
+			}),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.ValidateExamples())
+	})
+
+	t.Run("mounted command", func(t *testing.T) {
+		commit, err := NewCommand("commit", "record changes",
+			AddExample("git commit --message hi", ""),
+			AddFlag("message", "commit message"),
+			SetHandler(func(context.Context) error { return nil }),
+		)
+
+		require.NoError(t, err)
+
+		command, err := NewCommand("git", "git", MountSubCmd(commit))
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), []string{"commit", "--message", "hello"}))
+	})
+
+	t.Run("not checked by Run", func(t *testing.T) {
+		command, err := NewCommand("git", "git",
+			AddExample("git --verbose", ""),
+			SetHandler(func(context.Context) error { return nil }),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), nil))
+		assert.EqualError(t, command.ValidateExamples(), `invalid command "git": example "git --verbose" of "git": no flag found for "--verbose": invalid flag`)
+	})
+
+	t.Run("after a run", func(t *testing.T) {
+		var files []string
+		command, err := NewCommand("cat", "concatenate files",
+			AddExample("cat a b", ""),
+			AddFlag("color", "colorize", SetFlagDefault(true), SetFlagNegatable(true)),
+			AddArg("files", "files", SetArgVariadicCount(1, 2)),
+			SetHandler(func(ctx context.Context) error {
+				var err error
+				files, err = ArgValue[[]string](ctx, "files")
+				return err
+			}),
+		)
+
+		require.NoError(t, err)
+		require.NoError(t, command.Run(context.TODO(), []string{"--no-color", "x", "y"}))
+		assert.NoError(t, command.ValidateExamples())
+
+		require.NoError(t, command.Run(context.TODO(), []string{"z"}))
+		assert.Equal(t, []string{"z"}, files)
+	})
+}
+
+func TestCommand_renderHelp_examples(t *testing.T) {
+	command, err := NewCommand("test", "test command",
+		SetLongDescription("Test does many things.\nIt does them well."),
+		AddFlag("count", "how many", SetFlagDefault(1)),
+		AddExample("test --count 2", "Do it twice"),
+		AddExample("test", ""),
+		SetEpilog("See https://example.com for more."),
+	)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, command.renderHelp(buffer))
+	assert.Equal(t, heredoc.Doc(`
+		test: test command
+
+		Usage:
+		  test [flags]
+
+		Test does many things.
+		It does them well.
+
+		Flags:
+		  --count    how many  (type: int, default: "1")
+
+		Examples:
+		  # Do it twice
+		  test --count 2
+
+		  test
+
+		See https://example.com for more.
+
+	`), buffer.String())
+
+	manPage := new(bytes.Buffer)
+	require.NoError(t, command.renderManPage(manPage))
+	assert.Contains(t, manPage.String(), ".PP\nTest does many things.\nIt does them well.\n")
+	assert.Contains(t, manPage.String(), ".SH EXAMPLES\n.TP\n.B test \\-\\-count 2\nDo it twice\n")
+	assert.Contains(t, manPage.String(), ".SH NOTES\nSee https://example.com for more.\n")
+
+	markdown := new(bytes.Buffer)
+	require.NoError(t, command.renderMarkdown(markdown))
+	assert.Contains(t, markdown.String(), "test command\n\nTest does many things.\nIt does them well.\n")
+	assert.Contains(t, markdown.String(), "## Examples\n\nDo it twice\n\n```\ntest --count 2\n```\n\n```\ntest\n```\n")
+	assert.Contains(t, markdown.String(), "\n\nSee https://example.com for more.\n")
+}
@@ -1,21 +1,36 @@
 package cli
 
-import "github.com/bobg/errors"
+import (
+	"context"
 
-func (c *Command) validateInput() error {
-	validations := []func() error{
+	"github.com/bobg/errors"
+)
+
+func (c *Command) validateInput(ctx context.Context) error {
+	validations := []func(context.Context) error{
+		c.validateFlagsInput,
 		c.validateArgumentsInput,
+		c.validateFlagConstraintsInput,
 	}
 
 	var errs []error
 	for _, validation := range validations {
-		errs = append(errs, validation())
+		errs = append(errs, validation(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Command) validateFlagsInput(context.Context) error {
+	var errs []error
+	for _, flag := range c.flagsUpToRoot() {
+		errs = append(errs, flag.validateInput(c))
 	}
 
 	return errors.Join(errs...)
 }
 
-func (c *Command) validateArgumentsInput() error {
+func (c *Command) validateArgumentsInput(context.Context) error {
 	var errs []error
 	for _, argument := range c.arguments {
 		errs = append(errs, argument.validateInput())
@@ -23,3 +38,12 @@ func (c *Command) validateArgumentsInput() error {
 
 	return errors.Join(errs...)
 }
+
+func (c *Command) validateFlagConstraintsInput(ctx context.Context) error {
+	var errs []error
+	for _, constraint := range c.flagConstraintsUpToRoot() {
+		errs = append(errs, constraint.validateInput(ctx, c))
+	}
+
+	return errors.Join(errs...)
+}
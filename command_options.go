@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"context"
 	"sort"
+	"text/template"
 
+	"github.com/bobg/errors"
 	"github.com/broothie/option"
 )
 
@@ -24,6 +27,40 @@ func AddAlias(alias string) option.Func[*Command] {
 	}
 }
 
+// SetPrefixMatching controls whether a sub-command of the command, or of any of its descendants, can be selected by an
+// unambiguous prefix of its name or one of its aliases, e.g. "st" for "status".
+func SetPrefixMatching(isPrefixMatching bool) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.isPrefixMatching = isPrefixMatching
+		return command, nil
+	}
+}
+
+// SetLongDescription sets a longer description of the command, shown in its help, man page and markdown docs.
+func SetLongDescription(longDescription string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.longDescription = longDescription
+		return command, nil
+	}
+}
+
+// SetEpilog sets text shown at the end of the command's help, man page and markdown docs.
+func SetEpilog(epilog string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.epilog = epilog
+		return command, nil
+	}
+}
+
+// AddExample adds an example invocation of the command. commandLine is the full command line, starting with the root
+// command's name, and is checked against the command tree by ValidateExamples and when its docs are written.
+func AddExample(commandLine, explanation string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.examples = append(command.examples, Example{CommandLine: commandLine, Explanation: explanation})
+		return command, nil
+	}
+}
+
 // SetHandler sets the handler of the command.
 func SetHandler(handler Handler) option.Func[*Command] {
 	return func(command *Command) (*Command, error) {
@@ -32,6 +69,104 @@ func SetHandler(handler Handler) option.Func[*Command] {
 	}
 }
 
+// SetHandlerWith sets the handler of the command, passing it the command's flags and arguments bound to a T. See Bind.
+func SetHandlerWith[T any](handler func(ctx context.Context, input T) error) option.Func[*Command] {
+	return SetHandler(func(ctx context.Context) error {
+		input, err := Bind[T](ctx)
+		if err != nil {
+			return err
+		}
+
+		return handler(ctx, input)
+	})
+}
+
+// SetHelpTemplate sets the text/template used to render the help message of the command and its sub-commands.
+// The template is executed with a HelpData.
+func SetHelpTemplate(rawTemplate string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		parsedTemplate, err := template.New("help").Parse(rawTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing help template")
+		}
+
+		command.helpRenderer = templateHelpRenderer(parsedTemplate)
+		return command, nil
+	}
+}
+
+// SetHelpRenderer sets the function used to render the help message of the command and its sub-commands.
+func SetHelpRenderer(helpRenderer HelpRenderer) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.helpRenderer = helpRenderer
+		return command, nil
+	}
+}
+
+// SetTheme sets the styles used by the command and its sub-commands when color is enabled.
+func SetTheme(theme Theme) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.theme = &theme
+		return command, nil
+	}
+}
+
+// SetHelpWidth sets the width help of the command and its sub-commands is wrapped to, overriding $COLUMNS and the terminal width.
+// A width of 0 disables wrapping.
+func SetHelpWidth(width int) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		if width < 0 {
+			return nil, errors.Errorf("help width %d cannot be negative", width)
+		}
+
+		command.helpWidth = &width
+		return command, nil
+	}
+}
+
+// SetHelpSortOrder sets the order sub-commands and flags are listed in the help of the command and its sub-commands.
+func SetHelpSortOrder(order HelpSortOrder) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.helpSortOrder = &order
+		return command, nil
+	}
+}
+
+// SetCommandIsHidden controls whether the command is hidden from its parent's help, docs and completions.
+func SetCommandIsHidden(isHidden bool) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.isHidden = isHidden
+		return command, nil
+	}
+}
+
+// SetCommandDeprecated hides the command and makes running it print a warning with deprecation to stderr,
+// e.g. "use remove instead".
+func SetCommandDeprecated(deprecation string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.deprecation = deprecation
+		command.isHidden = true
+		return command, nil
+	}
+}
+
+// SetCommandReplacedBy forwards the command to the sibling sub-command with the given name or alias, so a renamed command
+// keeps working under its old name.
+func SetCommandReplacedBy(name string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.replacedBy = name
+		return command, nil
+	}
+}
+
+// SetCommandGroup sets the heading the command is listed under in its parent's help.
+func SetCommandGroup(group string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.group = group
+		return command, nil
+	}
+}
+
 // AddSubCmd adds a subcommand to the command.
 func AddSubCmd(name, description string, options ...option.Option[*Command]) option.Func[*Command] {
 	return func(command *Command) (*Command, error) {
@@ -81,7 +216,31 @@ func AddArg(name, description string, options ...option.Option[*Argument]) optio
 	}
 }
 
-// AddHelpFlag adds a help flag to the command.
+// AddMutuallyExclusiveFlags declares that at most one of the named flags may be set.
+func AddMutuallyExclusiveFlags(names ...string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.flagConstraints = append(command.flagConstraints, mutuallyExclusiveFlags(names))
+		return command, nil
+	}
+}
+
+// AddFlagsRequiredTogether declares that if any of the named flags is set, all of them must be.
+func AddFlagsRequiredTogether(names ...string) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.flagConstraints = append(command.flagConstraints, flagsRequiredTogether(names))
+		return command, nil
+	}
+}
+
+// AddFlagRequiredIf declares that the named flag is required when flag conditionName has value conditionValue.
+func AddFlagRequiredIf(name, conditionName string, conditionValue any) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		command.flagConstraints = append(command.flagConstraints, flagRequiredIf{name: name, conditionName: conditionName, conditionValue: conditionValue})
+		return command, nil
+	}
+}
+
+// AddHelpFlag adds a help flag to the command. Passing it as "--help=json" prints the command's Schema as JSON instead.
 func AddHelpFlag(options ...option.Option[*Flag]) option.Func[*Command] {
 	defaultOptions := option.NewOptions(setFlagIsHelp(true), SetFlagDefault(false))
 	return AddFlag(helpFlagName, "Print help.", append(defaultOptions, options...)...)
@@ -91,3 +250,41 @@ func AddVersionFlag(options ...option.Option[*Flag]) option.Func[*Command] {
 	defaultOptions := option.NewOptions(setFlagIsVersion(true), SetFlagDefault(false))
 	return AddFlag(versionFlagName, "Print version.", append(defaultOptions, options...)...)
 }
+
+// AddConfigFlag adds a "--config" flag pointing at a JSON, TOML or YAML config file, which is used as a source of flag values.
+// When the flag is not provided, the file is looked for at $XDG_CONFIG_HOME/<root name>/config.{json,toml,yaml,yml}.
+// Values are looked up by command path, so flag "target" of sub-command "proxy" is read from key "proxy.target".
+// Precedence is command line, then environment variable, then config file, then default.
+func AddConfigFlag(options ...option.Option[*Flag]) option.Func[*Command] {
+	defaultOptions := option.NewOptions(setFlagIsConfig(true), SetFlagIsInherited(true))
+	return AddFlag(configFlagName, "Path to config file.", append(defaultOptions, options...)...)
+}
+
+// AddColorFlag adds a "--color" flag controlling whether help and ActiveTheme are styled: "always", "never", or "auto",
+// which styles only when stdout is a terminal and $NO_COLOR is not set.
+func AddColorFlag(options ...option.Option[*Flag]) option.Func[*Command] {
+	defaultOptions := option.NewOptions(
+		setFlagIsColor(true),
+		SetFlagDefault(ColorAuto),
+		SetFlagChoices(ColorAuto, ColorAlways, ColorNever),
+		SetFlagIsInherited(true),
+	)
+
+	return AddFlag(colorFlagName, "When to use color.", append(defaultOptions, options...)...)
+}
+
+// AddCompletionCmd adds a "completion" sub-command which prints a completion script for bash, zsh or fish.
+// It also enables the hidden "__complete" entrypoint the scripts use to complete values at runtime.
+func AddCompletionCmd(options ...option.Option[*Command]) option.Func[*Command] {
+	defaultOptions := option.NewOptions(
+		AddArg(completionShellArg, "Shell to generate a completion script for (bash, zsh or fish).",
+			SetArgCompleter(completeShells),
+		),
+		SetHandler(runCompletionCommand),
+	)
+
+	return func(command *Command) (*Command, error) {
+		command.completionEnabled = true
+		return AddSubCmd(completionCommandName, "Generate a shell completion script.", append(defaultOptions, options...)...).Apply(command)
+	}
+}
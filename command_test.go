@@ -4,6 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"testing"
+
+	"github.com/broothie/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func ExampleNewCommand() {
@@ -61,3 +66,129 @@ func ExampleRun() {
 	// Output:
 	// hello
 }
+
+func TestCommand_findSubCommand(t *testing.T) {
+	newGit := func(t *testing.T, options ...option.Option[*Command]) *Command {
+		command, err := NewCommand("git", "the stupid content tracker", append(options,
+			AddSubCmd("status", "Show the working tree status", AddAlias("st")),
+			AddSubCmd("stash", "Stash the changes in a dirty working directory"),
+			AddSubCmd("commit", "Record changes to the repository", AddAlias("ci")),
+		)...)
+
+		require.NoError(t, err)
+		return command
+	}
+
+	type TestCase struct {
+		name          string
+		options       []option.Option[*Command]
+		expectedName  string
+		expectedFound bool
+		expectedError string
+	}
+
+	testCases := map[string]TestCase{
+		"name":                        {name: "commit", expectedName: "commit", expectedFound: true},
+		"alias":                       {name: "ci", expectedName: "commit", expectedFound: true},
+		"alias wins over prefix":      {name: "st", options: []option.Option[*Command]{SetPrefixMatching(true)}, expectedName: "status", expectedFound: true},
+		"prefix without opt-in":       {name: "com"},
+		"unique prefix":               {name: "com", options: []option.Option[*Command]{SetPrefixMatching(true)}, expectedName: "commit", expectedFound: true},
+		"unique prefix of alias":      {name: "c", options: []option.Option[*Command]{SetPrefixMatching(true)}, expectedName: "commit", expectedFound: true},
+		"ambiguous prefix":            {name: "sta", options: []option.Option[*Command]{SetPrefixMatching(true)}, expectedError: `"sta" could be "status", "stash": ambiguous sub-command`},
+		"no match with prefix opt-in": {name: "push", options: []option.Option[*Command]{SetPrefixMatching(true)}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, found, err := newGit(t, testCase.options...).findSubCommand(testCase.name)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedFound, found)
+			if found {
+				assert.Equal(t, testCase.expectedName, command.name)
+			}
+		})
+	}
+
+	t.Run("dispatch", func(t *testing.T) {
+		called := ensureCalled(t)
+		command, err := NewCommand("git", "the stupid content tracker",
+			SetPrefixMatching(true),
+			AddSubCmd("remote", "Manage remotes",
+				AddSubCmd("add", "Add a remote", SetHandler(func(context.Context) error {
+					called()
+					return nil
+				})),
+			),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), []string{"rem", "ad"}))
+	})
+}
+
+func TestCommand_Run_suggestions(t *testing.T) {
+	command, err := NewCommand("git", "the stupid content tracker",
+		AddFlag("verbose", "Be verbose", AddFlagShort('v'), SetFlagDefault(false), SetFlagIsInherited(true)),
+		AddFlag("debug", "Debug", SetFlagDefault(false), SetFlagIsHidden(true)),
+		AddSubCmd("status", "Show the working tree status", AddAlias("st")),
+		AddSubCmd("commit", "Record changes to the repository",
+			AddFlag("message", "commit message", AddFlagAlias("msg"), AddFlagShort('m')),
+			AddFlag("color", "colorize", SetFlagDefault(true), SetFlagNegatable(true)),
+		),
+	)
+
+	require.NoError(t, err)
+
+	type TestCase struct {
+		rawArgs       []string
+		expectedError string
+	}
+
+	testCases := map[string]TestCase{
+		"sub-command typo":        {rawArgs: []string{"stauts"}, expectedError: `"stauts" for "git" (did you mean "status"?): unknown sub-command`},
+		"unknown sub-command":     {rawArgs: []string{"frobnicate"}, expectedError: `"frobnicate" for "git": unknown sub-command`},
+		"long flag typo":          {rawArgs: []string{"commit", "--mesage", "hi"}, expectedError: `no flag found for "--mesage" (did you mean "--message"?): invalid flag`},
+		"alias typo":              {rawArgs: []string{"commit", "--mgs=hi"}, expectedError: `no flag found for "--mgs" (did you mean "--msg"?): invalid flag`},
+		"negation typo":           {rawArgs: []string{"commit", "--no-colr"}, expectedError: `no flag found for "--no-colr" (did you mean "--no-color"?): invalid flag`},
+		"inherited flag typo":     {rawArgs: []string{"commit", "--verbos"}, expectedError: `no flag found for "--verbos" (did you mean "--verbose"?): invalid flag`},
+		"long name for a short":   {rawArgs: []string{"commit", "--m", "hi"}, expectedError: `no flag found for "--m" (did you mean "-m"?): invalid flag`},
+		"unrelated single letter": {rawArgs: []string{"commit", "--z"}, expectedError: `no flag found for "--z": invalid flag`},
+		"hidden flag not offered": {rawArgs: []string{"--debgu"}, expectedError: `no flag found for "--debgu": invalid flag`},
+		"short flag case":         {rawArgs: []string{"commit", "-M", "hi"}, expectedError: `no short flag found for "-M" (did you mean "-m"?): invalid flag`},
+		"short flag case with equal": {
+			rawArgs:       []string{"commit", "-M=hi"},
+			expectedError: `no short flag found for "-M" (did you mean "-m"?): invalid flag`,
+		},
+		"long flag with one dash": {rawArgs: []string{"-verbos"}, expectedError: `no short flag found for "-e" (did you mean "--verbose"?): invalid flag`},
+		"unknown short flag":      {rawArgs: []string{"-x"}, expectedError: `no short flag found for "-x": invalid flag`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, command.Run(context.TODO(), testCase.rawArgs), testCase.expectedError)
+		})
+	}
+}
+
+func TestCommand_Run_restArgs(t *testing.T) {
+	var restArgs []string
+	command, err := NewCommand("exec", "run a program",
+		SetHandler(func(ctx context.Context) error {
+			var err error
+			restArgs, err = RestArgs(ctx)
+			return err
+		}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, command.Run(context.TODO(), []string{"--", "ls", "-l"}))
+	assert.Equal(t, []string{"ls", "-l"}, restArgs)
+
+	require.NoError(t, command.Run(context.TODO(), []string{"--", "pwd"}))
+	assert.Equal(t, []string{"pwd"}, restArgs)
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+const completeCommandName = "__complete"
+
+// CompletionHint tells the shell how to treat the completions offered for a value.
+type CompletionHint int
+
+const (
+	// CompletionHintNoSpace keeps the shell from adding a space after a completed value.
+	CompletionHintNoSpace CompletionHint = 1 << iota
+
+	// CompletionHintNoFiles keeps the shell from falling back to file completion.
+	CompletionHintNoFiles
+
+	// CompletionHintFilesOnly asks the shell to complete file names.
+	CompletionHintFilesOnly
+
+	// CompletionHintDirsOnly asks the shell to complete directory names.
+	CompletionHintDirsOnly
+)
+
+// Completion is a value offered to the shell during tab completion.
+// A Completion with an empty Value only contributes its Hint.
+type Completion struct {
+	Value       string
+	Description string
+	Hint        CompletionHint
+}
+
+// Completer returns the completions for a partially typed flag or argument value.
+type Completer func(ctx context.Context, partial string) []Completion
+
+// FileCompleter is a Completer which defers to the shell's file name completion.
+func FileCompleter(context.Context, string) []Completion {
+	return []Completion{{Hint: CompletionHintFilesOnly}}
+}
+
+// DirCompleter is a Completer which defers to the shell's directory name completion.
+func DirCompleter(context.Context, string) []Completion {
+	return []Completion{{Hint: CompletionHintDirsOnly}}
+}
+
+func (c *Command) isCompleteRequest(rawArgs []string) bool {
+	return c.isRoot() && c.completionEnabled && len(rawArgs) > 0 && rawArgs[0] == completeCommandName
+}
+
+func (c *Command) writeCompletions(ctx context.Context, w io.Writer, args []string) error {
+	completions, hint := c.complete(ctx, args)
+	for _, completion := range completions {
+		line := completion.Value
+		if completion.Description != "" {
+			line = fmt.Sprintf("%s\t%s", line, completion.Description)
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return errors.Wrap(err, "writing completion")
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, ":%d\n", hint); err != nil {
+		return errors.Wrap(err, "writing completion hint")
+	}
+
+	return nil
+}
+
+func (c *Command) complete(ctx context.Context, args []string) ([]Completion, CompletionHint) {
+	tokens, partial := args, ""
+	if len(args) > 0 {
+		tokens, partial = args[:len(args)-1], args[len(args)-1]
+	}
+
+	var completions []Completion
+	var hint CompletionHint
+	p := c.newParser(tokens)
+	p.runSubCommand = func(command *Command, ctx context.Context, tokens []string) error {
+		completions, hint = command.complete(ctx, append(slices.Clone(tokens), partial))
+		return nil
+	}
+
+	for p.index < len(p.tokens) {
+		if flag, found := p.flagAwaitingValue(); found {
+			return flag.complete(c.onContext(ctx), partial)
+		}
+
+		if commandProcessed, err := p.parseArg(ctx); err != nil {
+			return nil, CompletionHintNoFiles
+		} else if commandProcessed {
+			return completions, hint
+		}
+	}
+
+	return p.completePartial(ctx, partial)
+}
+
+func (p *parser) flagAwaitingValue() (*Flag, bool) {
+	current, _ := p.current()
+	if p.isTerminated || p.index != len(p.tokens)-1 || !strings.HasPrefix(current, flagPrefix) || strings.Contains(current, "=") {
+		return nil, false
+	}
+
+	if strings.HasPrefix(current, longFlagPrefix) {
+		flag, found := p.command.findLongFlag(strings.TrimPrefix(current, longFlagPrefix))
+		return flag, found && flag.takesValue()
+	}
+
+	for _, short := range strings.TrimPrefix(current, flagPrefix) {
+		if flag, found := p.command.findShortFlag(short); found && flag.takesValue() {
+			return flag, true
+		}
+	}
+
+	return nil, false
+}
+
+func (p *parser) completePartial(ctx context.Context, partial string) ([]Completion, CompletionHint) {
+	ctx = p.command.onContext(ctx)
+
+	if p.isTerminated {
+		if p.argumentIndex < len(p.command.arguments) {
+			return p.command.arguments[p.argumentIndex].complete(ctx, partial)
+		}
+
+		return nil, 0
+	}
+
+	if strings.HasPrefix(partial, longFlagPrefix) && strings.Contains(partial, "=") {
+		rawFlag, rawValue, _ := strings.Cut(partial, "=")
+		flag, found := p.command.findLongFlag(strings.TrimPrefix(rawFlag, longFlagPrefix))
+		if !found || !flag.takesValue() {
+			return nil, CompletionHintNoFiles
+		}
+
+		completions, hint := flag.complete(ctx, rawValue)
+		return lo.Map(completions, func(completion Completion, _ int) Completion {
+			completion.Value = fmt.Sprintf("%s=%s", rawFlag, completion.Value)
+			return completion
+		}), hint
+	}
+
+	if strings.HasPrefix(partial, flagPrefix) {
+		return p.command.flagCompletions(partial), CompletionHintNoFiles
+	}
+
+	completions := p.command.subCommandCompletions(partial)
+	if p.argumentIndex < len(p.command.arguments) {
+		argumentCompletions, hint := p.command.arguments[p.argumentIndex].complete(ctx, partial)
+		return append(completions, argumentCompletions...), hint
+	}
+
+	return completions, CompletionHintNoFiles
+}
+
+func (c *Command) flagCompletions(partial string) []Completion {
+	var completions []Completion
+	for _, flag := range c.completionCommand().Flags {
+		for _, name := range flag.dashed() {
+			if strings.HasPrefix(name, partial) {
+				completions = append(completions, Completion{Value: name, Description: flag.Description})
+			}
+		}
+	}
+
+	return completions
+}
+
+func (c *Command) subCommandCompletions(partial string) []Completion {
+	return lo.FilterMap(c.completionCommand().SubCommands, func(subCommand completionSubCommand, _ int) (Completion, bool) {
+		return Completion{Value: subCommand.Name, Description: subCommand.Description}, strings.HasPrefix(subCommand.Name, partial)
+	})
+}
+
+func (f *Flag) complete(ctx context.Context, partial string) ([]Completion, CompletionHint) {
+	if f.completer == nil {
+		if choices := f.allowedChoices(ctx); len(choices) > 0 {
+			return choicesCompletions(choices, partial), CompletionHintNoFiles
+		}
+
+		return nil, 0
+	}
+
+	return collectCompletions(f.completer(ctx, partial))
+}
+
+func (a *Argument) complete(ctx context.Context, partial string) ([]Completion, CompletionHint) {
+	if a.completer == nil {
+		if choices := a.allowedChoices(ctx); len(choices) > 0 {
+			return choicesCompletions(choices, partial), CompletionHintNoFiles
+		}
+
+		return nil, 0
+	}
+
+	return collectCompletions(a.completer(ctx, partial))
+}
+
+func collectCompletions(completions []Completion) ([]Completion, CompletionHint) {
+	var hint CompletionHint
+	for _, completion := range completions {
+		hint |= completion.Hint
+	}
+
+	return lo.Filter(completions, func(completion Completion, _ int) bool { return completion.Value != "" }), hint
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_complete(t *testing.T) {
+	clusters := []string{"prod-east", "prod-west", "staging"}
+	completeClusters := func(ctx context.Context, partial string) []Completion {
+		return lo.FilterMap(clusters, func(cluster string, _ int) (Completion, bool) {
+			return Completion{Value: cluster, Description: "cluster"}, strings.HasPrefix(cluster, partial)
+		})
+	}
+
+	command, err := NewCommand("kube", "cluster tool",
+		AddHelpFlag(AddFlagShort('h'), SetFlagIsInherited(true)),
+		AddFlag("cluster", "Cluster to use",
+			AddFlagShort('c'),
+			SetFlagIsInherited(true),
+			SetFlagCompleter(completeClusters),
+		),
+		AddFlag("verbose", "Be verbose", AddFlagShort('v'), SetFlagDefault(false), SetFlagIsInherited(true)),
+		AddSubCmd("apply", "Apply a manifest",
+			AddFlag("output", "Output format", SetFlagChoices("json", "yaml")),
+			AddArg("manifest", "Manifest to apply", SetArgCompleter(FileCompleter)),
+		),
+		AddSubCmd("logs", "Print logs",
+			AddArg("pod", "Pod to print logs for", SetArgCompleter(func(ctx context.Context, partial string) []Completion {
+				cluster, err := FlagValue[string](ctx, "cluster")
+				require.NoError(t, err)
+
+				return []Completion{{Value: cluster + "-pod", Hint: CompletionHintNoSpace}}
+			})),
+		),
+		AddCompletionCmd(),
+	)
+
+	require.NoError(t, err)
+
+	type TestCase struct {
+		args                []string
+		expectedCompletions []string
+		expectedHint        CompletionHint
+	}
+
+	testCases := map[string]TestCase{
+		"sub-commands": {
+			args:                []string{""},
+			expectedCompletions: []string{"apply", "logs", "completion"},
+			expectedHint:        CompletionHintNoFiles,
+		},
+		"sub-command prefix": {
+			args:                []string{"a"},
+			expectedCompletions: []string{"apply"},
+			expectedHint:        CompletionHintNoFiles,
+		},
+		"flags": {
+			args:                []string{"--c"},
+			expectedCompletions: []string{"--cluster"},
+			expectedHint:        CompletionHintNoFiles,
+		},
+		"long flag value": {
+			args:                []string{"--cluster", "prod"},
+			expectedCompletions: []string{"prod-east", "prod-west"},
+		},
+		"long flag value with equal sign": {
+			args:                []string{"--cluster=s"},
+			expectedCompletions: []string{"--cluster=staging"},
+		},
+		"short flag group value": {
+			args:                []string{"-vc", ""},
+			expectedCompletions: clusters,
+		},
+		"inherited flag value in sub-command": {
+			args:                []string{"logs", "-c", "st"},
+			expectedCompletions: []string{"staging"},
+		},
+		"argument hint": {
+			args:         []string{"apply", ""},
+			expectedHint: CompletionHintFilesOnly,
+		},
+		"argument sees parsed flags": {
+			args:                []string{"logs", "--cluster", "staging", ""},
+			expectedCompletions: []string{"staging-pod"},
+			expectedHint:        CompletionHintNoSpace,
+		},
+		"completion shell argument": {
+			args:                []string{"completion", "--verbose", "z"},
+			expectedCompletions: []string{"bash", "fish", "zsh"},
+		},
+		"after terminator": {
+			args:         []string{"apply", "--", "-"},
+			expectedHint: CompletionHintFilesOnly,
+		},
+		"flag choices": {
+			args:                []string{"apply", "--output", "j"},
+			expectedCompletions: []string{"json"},
+			expectedHint:        CompletionHintNoFiles,
+		},
+		"invalid flag": {
+			args:         []string{"--nope", ""},
+			expectedHint: CompletionHintNoFiles,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			completions, hint := command.complete(context.TODO(), testCase.args)
+
+			assert.ElementsMatch(t, testCase.expectedCompletions, lo.Map(completions, func(completion Completion, _ int) string { return completion.Value }))
+			assert.Equal(t, testCase.expectedHint, hint)
+		})
+	}
+}
+
+func TestCommand_writeCompletions(t *testing.T) {
+	command, err := NewCommand("kube", "cluster tool",
+		AddFlag("cluster", "Cluster to use"),
+		AddSubCmd("apply", "Apply a manifest"),
+		AddCompletionCmd(),
+	)
+
+	require.NoError(t, err)
+	assert.True(t, command.isCompleteRequest([]string{completeCommandName, ""}))
+	assert.False(t, command.subCommands[0].isCompleteRequest([]string{completeCommandName, ""}))
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, command.writeCompletions(context.TODO(), buffer, []string{"-"}))
+	assert.Equal(t,
+		heredoc.Doc(`
+			--cluster	Cluster to use
+			:2
+		`),
+		buffer.String(),
+	)
+}
+
+func TestCommand_isCompleteRequest(t *testing.T) {
+	command, err := NewCommand("kube", "cluster tool", AddArg("name", "name"))
+	require.NoError(t, err)
+
+	assert.False(t, command.isCompleteRequest([]string{completeCommandName, ""}))
+}
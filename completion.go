@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+const (
+	completionCommandName = "completion"
+	completionShellArg    = "shell"
+)
+
+var UnsupportedShellError = errors.New("unsupported shell")
+
+var (
+	//go:embed completion.bash.tmpl
+	rawBashCompletionTemplate string
+
+	//go:embed completion.zsh.tmpl
+	rawZshCompletionTemplate string
+
+	//go:embed completion.fish.tmpl
+	rawFishCompletionTemplate string
+)
+
+var completionTemplateFuncs = template.FuncMap{
+	"quote":       shellQuote,
+	"doubleQuote": doubleQuote,
+	"join":        strings.Join,
+}
+
+var completionTemplates = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Funcs(completionTemplateFuncs).Parse(rawBashCompletionTemplate)),
+	"zsh":  template.Must(template.New("zsh").Funcs(completionTemplateFuncs).Parse(rawZshCompletionTemplate)),
+	"fish": template.Must(template.New("fish").Funcs(completionTemplateFuncs).Parse(rawFishCompletionTemplate)),
+}
+
+var nonIdentifierPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// WriteCompletion writes a completion script for the given shell (bash, zsh or fish) covering the whole command tree.
+func (c *Command) WriteCompletion(w io.Writer, shell string) error {
+	completionTemplate, found := completionTemplates[shell]
+	if !found {
+		return errors.Wrapf(UnsupportedShellError, "shell %q", shell)
+	}
+
+	if err := completionTemplate.Execute(w, c.root().completionContext()); err != nil {
+		return errors.Wrapf(err, "%s completion template", shell)
+	}
+
+	return nil
+}
+
+func runCompletionCommand(ctx context.Context) error {
+	command, err := commandFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	shell, err := ArgValue[string](ctx, completionShellArg)
+	if err != nil {
+		return err
+	}
+
+	return command.WriteCompletion(os.Stdout, shell)
+}
+
+func completeShells(context.Context, string) []Completion {
+	shells := lo.Keys(completionTemplates)
+	sort.Strings(shells)
+
+	return lo.Map(shells, func(shell string, _ int) Completion { return Completion{Value: shell} })
+}
+
+type completionContext struct {
+	RootName            string
+	FuncName            string
+	CompleteCommandName string
+	Commands            []completionCommand
+
+	NoSpaceHint   CompletionHint
+	NoFilesHint   CompletionHint
+	FilesOnlyHint CompletionHint
+	DirsOnlyHint  CompletionHint
+}
+
+type completionCommand struct {
+	Path         string
+	SubCommands  []completionSubCommand
+	Flags        []completionFlag
+	HasArguments bool
+}
+
+type completionSubCommand struct {
+	Name        string
+	Aliases     []string
+	Description string
+}
+
+type completionRoute struct {
+	Patterns []string
+	Path     string
+}
+
+type completionFlag struct {
+	Longs       []string
+	Shorts      []string
+	Description string
+	TakesValue  bool
+}
+
+func (c *Command) completionContext() completionContext {
+	var commands []completionCommand
+	c.walk(func(command *Command) {
+		commands = append(commands, command.completionCommand())
+	})
+
+	return completionContext{
+		RootName:            c.name,
+		FuncName:            fmt.Sprintf("_%s", nonIdentifierPattern.ReplaceAllString(c.name, "_")),
+		CompleteCommandName: completeCommandName,
+		Commands:            commands,
+
+		NoSpaceHint:   CompletionHintNoSpace,
+		NoFilesHint:   CompletionHintNoFiles,
+		FilesOnlyHint: CompletionHintFilesOnly,
+		DirsOnlyHint:  CompletionHintDirsOnly,
+	}
+}
+
+func (c *Command) completionCommand() completionCommand {
+	return completionCommand{
+		Path: c.qualifiedName(),
+		SubCommands: lo.Map(c.visibleSubCommands(), func(command *Command, _ int) completionSubCommand {
+			return completionSubCommand{Name: command.name, Aliases: command.aliases, Description: command.description}
+		}),
+		Flags: lo.FilterMap(c.flagsUpToRoot(), func(flag *Flag, _ int) (completionFlag, bool) {
+			if flag.isHidden {
+				return completionFlag{}, false
+			}
+
+			return completionFlag{
+				Longs:       flag.longNames(),
+				Shorts:      lo.Map(flag.shorts, func(short rune, _ int) string { return string(short) }),
+				Description: flag.description,
+				TakesValue:  flag.takesValue(),
+			}, true
+		}),
+		HasArguments: len(c.arguments) > 0,
+	}
+}
+
+func (c completionContext) SubCommandRoutes() []completionRoute {
+	var routes []completionRoute
+	for _, command := range c.Commands {
+		for _, subCommand := range command.SubCommands {
+			routes = append(routes, completionRoute{
+				Patterns: lo.Map(append([]string{subCommand.Name}, subCommand.Aliases...), func(name string, _ int) string {
+					return fmt.Sprintf("%s %s", command.Path, name)
+				}),
+				Path: fmt.Sprintf("%s %s", command.Path, subCommand.Name),
+			})
+		}
+	}
+
+	return routes
+}
+
+func (c completionCommand) SubCommandNames() []string {
+	return lo.Map(c.SubCommands, func(subCommand completionSubCommand, _ int) string { return subCommand.Name })
+}
+
+func (c completionCommand) FlagNames() []string {
+	var flagNames []string
+	for _, flag := range c.Flags {
+		flagNames = append(flagNames, flag.dashed()...)
+	}
+
+	return flagNames
+}
+
+func (c completionCommand) ValueFlags() []string {
+	var valueFlags []string
+	for _, flag := range c.Flags {
+		if flag.TakesValue {
+			valueFlags = append(valueFlags, flag.dashed()...)
+		}
+	}
+
+	return valueFlags
+}
+
+func (c completionCommand) ZshSubCommands() []string {
+	return lo.Map(c.SubCommands, func(subCommand completionSubCommand, _ int) string {
+		return fmt.Sprintf("%s:%s", zshEscape(subCommand.Name), subCommand.Description)
+	})
+}
+
+func (c completionCommand) ZshFlags() []string {
+	var flags []string
+	for _, flag := range c.Flags {
+		for _, dashed := range flag.dashed() {
+			flags = append(flags, fmt.Sprintf("%s:%s", zshEscape(dashed), flag.Description))
+		}
+	}
+
+	return flags
+}
+
+func (f completionFlag) dashed() []string {
+	longs := lo.Map(f.Longs, func(long string, _ int) string { return fmt.Sprintf("--%s", long) })
+	shorts := lo.Map(f.Shorts, func(short string, _ int) string { return fmt.Sprintf("-%s", short) })
+	return append(longs, shorts...)
+}
+
+func shellQuote(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", `'\''`))
+}
+
+func doubleQuote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(s))
+}
+
+func zshEscape(s string) string {
+	return strings.ReplaceAll(s, ":", `\:`)
+}
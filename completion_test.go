@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_WriteCompletion(t *testing.T) {
+	command, err := NewCommand("git", "the stupid content tracker",
+		AddHelpFlag(AddFlagShort('h'), SetFlagIsInherited(true)),
+		AddFlag("git-dir", "Git directory to use"),
+		AddFlag("debug", "Enable debugging", SetFlagDefault(false), SetFlagIsHidden(true)),
+		AddSubCmd("commit", "Record changes to the repository",
+			AddAlias("ci"),
+			AddFlag("message", "commit message",
+				AddFlagAlias("msg"),
+				AddFlagShort('m'),
+			),
+			AddFlag("all", "commit all changed files",
+				AddFlagShort('a'),
+				SetFlagDefault(false),
+			),
+		),
+		AddSubCmd("checkout", "Switch branches or restore working tree files",
+			AddArg("branch", "Branch to check out"),
+		),
+		AddCompletionCmd(),
+	)
+
+	require.NoError(t, err)
+
+	t.Run("bash", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.WriteCompletion(buffer, "bash"))
+
+		script := buffer.String()
+		assert.Contains(t, script, "'git commit'|'git ci')\n\t\t\t\tcmd_path='git commit'")
+		assert.Contains(t, script, `sub_commands='commit checkout completion'`)
+		assert.Contains(t, script, `flags='--help -h --git-dir'`)
+		assert.Contains(t, script, `flags='--message --msg -m --all -a --help -h'`)
+		assert.Contains(t, script, `value_flags='--message --msg -m'`)
+		assert.Contains(t, script, "complete -o default -F _git_completion git")
+		assert.NotContains(t, script, "--debug")
+	})
+
+	t.Run("zsh", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.WriteCompletion(buffer, "zsh"))
+
+		script := buffer.String()
+		assert.Contains(t, script, "#compdef git")
+		assert.Contains(t, script, `'commit:Record changes to the repository'`)
+		assert.Contains(t, script, `'--msg:commit message'`)
+		assert.Contains(t, script, "'git commit'|'git ci')\n\t\t\t\tcmd_path='git commit'")
+		assert.Contains(t, script, "compdef _git git")
+		assert.NotContains(t, script, "--debug")
+	})
+
+	t.Run("fish", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.WriteCompletion(buffer, "fish"))
+
+		script := buffer.String()
+		assert.Contains(t, script, "case 'git commit' 'git ci'\n\t\t\t\tset cmd_path 'git commit'")
+		assert.Contains(t, script, `complete -c git -n "_git_using_cmd 'git'" -a 'commit' -d 'Record changes to the repository'`)
+		assert.Contains(t, script, `complete -c git -n "_git_using_cmd 'git commit'" -l 'message' -l 'msg' -s 'm' -r -a '(_git_dynamic)' -d 'commit message'`)
+		assert.Contains(t, script, `complete -c git -n "_git_using_cmd 'git commit'" -l 'help' -s 'h' -d 'Print help.'`)
+		assert.Contains(t, script, `complete -c git -n "_git_using_cmd 'git checkout'" -a '(_git_dynamic)'`)
+		assert.NotContains(t, script, "debug")
+	})
+
+	t.Run("from sub-command", func(t *testing.T) {
+		fromRoot := new(bytes.Buffer)
+		require.NoError(t, command.WriteCompletion(fromRoot, "bash"))
+
+		fromSubCommand := new(bytes.Buffer)
+		require.NoError(t, command.subCommands[0].WriteCompletion(fromSubCommand, "bash"))
+
+		assert.Equal(t, fromRoot.String(), fromSubCommand.String())
+	})
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		err := command.WriteCompletion(new(bytes.Buffer), "powershell")
+		assert.ErrorIs(t, err, UnsupportedShellError)
+	})
+}
+
+func TestAddCompletionCmd(t *testing.T) {
+	command, err := NewCommand("git", "the stupid content tracker", AddCompletionCmd())
+	require.NoError(t, err)
+
+	err = command.Run(context.TODO(), []string{"completion", "tcsh"})
+	assert.EqualError(t, err, `shell "tcsh": unsupported shell`)
+}
+
+func Test_shellQuote(t *testing.T) {
+	assert.Equal(t, `'plain'`, shellQuote("plain"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
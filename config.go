@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+const configFlagName = "config"
+
+var UnsupportedConfigFormatError = errors.New("unsupported config format")
+
+var configDecoders = map[string]func([]byte, any) error{
+	".json": json.Unmarshal,
+	".toml": toml.Unmarshal,
+	".yaml": yaml.Unmarshal,
+	".yml":  yaml.Unmarshal,
+}
+
+var configExtensions = []string{".json", ".toml", ".yaml", ".yml"}
+
+func (c *Command) loadConfig(ctx context.Context) error {
+	configFlag, found := c.findFlagUpToRoot(func(flag *Flag) bool { return flag.isConfig })
+	if !found {
+		return nil
+	}
+
+	value, err := c.flagValue(ctx, configFlag)
+	if err != nil {
+		return err
+	}
+
+	path, isExplicit := fmt.Sprint(value), true
+	if path == "" {
+		if path, found = c.findConfigFile(); !found {
+			return nil
+		}
+
+		isExplicit = false
+	}
+
+	config, err := readConfigFile(path)
+	if errors.Is(err, os.ErrNotExist) && !isExplicit {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "loading config %q", path)
+	}
+
+	c.root().config = config
+	return nil
+}
+
+func (c *Command) findConfigFile() (string, bool) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	for _, extension := range configExtensions {
+		path := filepath.Join(configHome, c.root().name, fmt.Sprintf("config%s", extension))
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+func readConfigFile(path string) (map[string]any, error) {
+	decode, found := configDecoders[filepath.Ext(path)]
+	if !found {
+		return nil, errors.Wrapf(UnsupportedConfigFormatError, "extension %q", filepath.Ext(path))
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]any)
+	if err := decode(contents, &config); err != nil {
+		return nil, errors.Wrap(err, "decoding config")
+	}
+
+	return config, nil
+}
+
+func (c *Command) configValue(flag *Flag) (any, bool) {
+	owner, found := c.findFlagOwner(flag)
+	if !found {
+		return nil, false
+	}
+
+	var current any = c.root().config
+	for _, key := range append(owner.commandPath(), flag.name) {
+		table, isTable := current.(map[string]any)
+		if !isTable {
+			return nil, false
+		}
+
+		if current, found = table[key]; !found {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func (c *Command) findFlagOwner(flag *Flag) (*Command, bool) {
+	for current := c; current != nil; current = current.parent {
+		for _, currentFlag := range current.flags {
+			if currentFlag == flag {
+				return current, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (c *Command) commandPath() []string {
+	if c.isRoot() {
+		return nil
+	}
+
+	return append(c.parent.commandPath(), c.name)
+}
+
+func (f *Flag) parseConfigValue(configValue any) (any, error) {
+	parser, isAccumulating := f.parser.(accumulatingArgParser)
+	if !isAccumulating {
+		return f.parser.Parse(configValueToString(configValue))
+	}
+
+	var rawValues []string
+	switch configValue := configValue.(type) {
+	case []any:
+		rawValues = lo.Map(configValue, func(element any, _ int) string { return configValueToString(element) })
+
+	case map[string]any:
+		for _, key := range lo.Keys(configValue) {
+			rawValues = append(rawValues, fmt.Sprintf("%s=%s", key, configValueToString(configValue[key])))
+		}
+
+		sort.Strings(rawValues)
+
+	default:
+		return parser.accumulate(nil, configValueToString(configValue))
+	}
+
+	value, err := parser.accumulate(nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rawValue := range rawValues {
+		if value, err = parser.appendElement(value, rawValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+func configValueToString(configValue any) string {
+	switch configValue := configValue.(type) {
+	case float64:
+		return strconv.FormatFloat(configValue, 'f', -1, 64)
+
+	case time.Time:
+		return configValue.Format(time.RFC3339Nano)
+
+	default:
+		return fmt.Sprint(configValue)
+	}
+}
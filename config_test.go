@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func Test_config(t *testing.T) {
+	type Values struct {
+		port    int
+		host    string
+		target  string
+		timeout string
+	}
+
+	configFiles := map[string]string{
+		"config.json": heredoc.Doc(`
+			{
+				"port": 8080,
+				"host": "example.com",
+				"proxy": {"target": "https://proxy.example.com", "timeout": "5s"}
+			}
+		`),
+		"config.toml": heredoc.Doc(`
+			port = 8080
+			host = "example.com"
+
+			[proxy]
+			target = "https://proxy.example.com"
+			timeout = "5s"
+		`),
+		"config.yaml": heredoc.Doc(`
+			port: 8080
+			host: example.com
+			proxy:
+			  target: https://proxy.example.com
+			  timeout: 5s
+		`),
+	}
+
+	run := func(t *testing.T, rawArgs []string) Values {
+		var values Values
+		command, err := NewCommand("server", "An http server.",
+			AddConfigFlag(),
+			AddFlag("port", "Port", SetFlagDefault(3000), SetFlagDefaultEnv("TEST_CONFIG_PORT"), SetFlagIsInherited(true)),
+			AddFlag("host", "Host", SetFlagDefault("localhost"), SetFlagIsInherited(true)),
+			AddSubCmd("proxy", "Proxy requests",
+				AddFlag("target", "Target"),
+				AddFlag("timeout", "Timeout", SetFlagDefault(time.Second)),
+				SetHandler(func(ctx context.Context) error {
+					var err error
+					values.port, err = FlagValue[int](ctx, "port")
+					require.NoError(t, err)
+
+					values.host, err = FlagValue[string](ctx, "host")
+					require.NoError(t, err)
+
+					values.target, err = FlagValue[string](ctx, "target")
+					require.NoError(t, err)
+
+					timeout, err := FlagValue[time.Duration](ctx, "timeout")
+					require.NoError(t, err)
+					values.timeout = timeout.String()
+
+					return nil
+				}),
+			),
+		)
+
+		require.NoError(t, err)
+		require.NoError(t, command.Run(context.TODO(), rawArgs))
+		return values
+	}
+
+	for name, contents := range configFiles {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfigFile(t, t.TempDir(), name, contents)
+
+			assert.Equal(t,
+				Values{port: 8080, host: "example.com", target: "https://proxy.example.com", timeout: "5s"},
+				run(t, []string{"--config", path, "proxy"}),
+			)
+		})
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		path := writeConfigFile(t, t.TempDir(), "config.json", configFiles["config.json"])
+		t.Setenv("TEST_CONFIG_PORT", "9090")
+
+		assert.Equal(t,
+			Values{port: 9090, host: "cli.example.com", target: "https://proxy.example.com", timeout: "5s"},
+			run(t, []string{"--config", path, "proxy", "--host", "cli.example.com"}),
+		)
+	})
+
+	t.Run("empty env", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		t.Setenv("TEST_CONFIG_HOST", "")
+
+		command, err := NewCommand("server", "An http server.",
+			AddFlag("host", "Host", SetFlagDefault("localhost"), SetFlagDefaultEnv("TEST_CONFIG_HOST")),
+			SetHandler(func(ctx context.Context) error {
+				host, err := FlagValue[string](ctx, "host")
+				require.NoError(t, err)
+				assert.Equal(t, "", host)
+				return nil
+			}),
+		)
+
+		require.NoError(t, err)
+		require.NoError(t, command.Run(context.TODO(), nil))
+	})
+
+	t.Run("xdg config home", func(t *testing.T) {
+		configHome := t.TempDir()
+		writeConfigFile(t, configHome, filepath.Join("server", "config.yaml"), configFiles["config.yaml"])
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		assert.Equal(t,
+			Values{port: 8080, host: "example.com", target: "https://proxy.example.com", timeout: "5s"},
+			run(t, []string{"proxy"}),
+		)
+	})
+
+	t.Run("no config", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		assert.Equal(t,
+			Values{port: 3000, host: "localhost", target: "", timeout: "1s"},
+			run(t, []string{"proxy"}),
+		)
+	})
+}
+
+func TestCommand_loadConfig(t *testing.T) {
+	command, err := NewCommand("server", "An http server.", AddConfigFlag())
+	require.NoError(t, err)
+
+	t.Run("missing explicit config", func(t *testing.T) {
+		err := command.Run(context.TODO(), []string{"--config", filepath.Join(t.TempDir(), "config.json")})
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		path := writeConfigFile(t, t.TempDir(), "config.ini", "port = 8080")
+
+		err := command.Run(context.TODO(), []string{"--config", path})
+		assert.ErrorIs(t, err, UnsupportedConfigFormatError)
+	})
+}
+
+func TestFlag_parseConfigValue(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		flag, err := newFlag("tag", "tag", SetFlagDefault([]int{}))
+		require.NoError(t, err)
+
+		value, err := flag.parseConfigValue([]any{float64(1), float64(2)})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, value)
+
+		value, err = flag.parseConfigValue([]any{})
+		require.NoError(t, err)
+		assert.Equal(t, []int{}, value)
+	})
+
+	t.Run("slice elements with commas", func(t *testing.T) {
+		flag, err := newFlag("include", "include", SetFlagDefault([]string{}))
+		require.NoError(t, err)
+
+		value, err := flag.parseConfigValue([]any{"x,y", "z"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"x,y", "z"}, value)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		flag, err := newFlag("label", "label", SetFlagDefault(map[string]string{}))
+		require.NoError(t, err)
+
+		value, err := flag.parseConfigValue(map[string]any{"env": "prod", "replicas": 3})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"env": "prod", "replicas": "3"}, value)
+
+		value, err = flag.parseConfigValue(map[string]any{"hosts": "a,b"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"hosts": "a,b"}, value)
+	})
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"os"
 
 	"github.com/bobg/errors"
 )
@@ -26,20 +25,12 @@ func FlagValue[T any](ctx context.Context, name string) (T, error) {
 		return zero, errors.Wrapf(FlagNotFoundError, "finding flag %q", name)
 	}
 
-	if flag.value != nil {
-		return flag.value.(T), nil
-	}
-
-	if flag.defaultEnvName != "" {
-		value, err := flag.parser.Parse(os.Getenv(flag.defaultEnvName))
-		if err != nil {
-			return zero, err
-		}
-
-		return value.(T), nil
+	value, err := command.flagValue(ctx, flag)
+	if err != nil {
+		return zero, err
 	}
 
-	return flag.defaultValue.(T), nil
+	return value.(T), nil
 }
 
 func ArgValue[T any](ctx context.Context, name string) (T, error) {
@@ -55,11 +46,22 @@ func ArgValue[T any](ctx context.Context, name string) (T, error) {
 		return zero, errors.Wrapf(ArgumentNotFoundError, "finding argument %q", name)
 	}
 
-	if arg.value != nil {
-		return arg.value.(T), nil
+	value := arg.valueOrDefault()
+	if value == nil {
+		return zero, nil
+	}
+
+	return value.(T), nil
+}
+
+// RestArgs returns the arguments following a "--" terminator which were not consumed by the command's arguments.
+func RestArgs(ctx context.Context) ([]string, error) {
+	command, err := commandFromContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding rest args")
 	}
 
-	return arg.defaultValue.(T), nil
+	return command.restArgs, nil
 }
 
 type commandContextKeyType struct{}
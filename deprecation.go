@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+// warnDeprecated writes a deprecation warning to w. Warnings are best effort, so a failed write doesn't stop the
+// command.
+func warnDeprecated(w io.Writer, subject, deprecation string) {
+	_, _ = fmt.Fprintf(w, "warning: %s is deprecated: %s\n", subject, deprecation)
+}
+
+// useFlag warns if flag, given on the command line as rawFlag, is deprecated, and returns the flag it's forwarded to.
+func (p *parser) useFlag(flag *Flag, rawFlag string) *Flag {
+	if flag.deprecation != "" {
+		warnDeprecated(p.warnings, fmt.Sprintf("flag %q", rawFlag), flag.deprecation)
+	}
+
+	if flag.replacedBy == "" {
+		return flag
+	}
+
+	if replacement, found := p.command.findLongFlag(flag.replacedBy); found {
+		return replacement
+	}
+
+	return flag
+}
+
+// useCommand warns if command is deprecated, and returns the sibling it's forwarded to.
+func (p *parser) useCommand(command *Command) *Command {
+	if command.deprecation != "" {
+		warnDeprecated(p.warnings, fmt.Sprintf("command %q", command.qualifiedName()), command.deprecation)
+	}
+
+	if command.replacedBy == "" {
+		return command
+	}
+
+	if replacement, found := p.command.findReplacementSubCommand(command.replacedBy); found {
+		return replacement
+	}
+
+	return command
+}
+
+func (p *parser) useArgument(argument *Argument) {
+	if argument.deprecation != "" && argument.value == nil {
+		warnDeprecated(p.warnings, fmt.Sprintf("argument %q", argument.name), argument.deprecation)
+	}
+}
+
+func (c *Command) findReplacementSubCommand(name string) (*Command, bool) {
+	return lo.Find(c.subCommands, func(subCommand *Command) bool { return subCommand.hasName(name) })
+}
+
+func (c *Command) validateSubCommandReplacements() error {
+	var errs []error
+	for _, command := range c.subCommands {
+		if command.replacedBy == "" {
+			continue
+		}
+
+		if _, found := c.findReplacementSubCommand(command.replacedBy); !found {
+			errs = append(errs, errors.Errorf("sub-command %q replaced by unknown sub-command %q", command.name, command.replacedBy))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateFlagReplacements checks that the flags of every command in the tree are replaced by flags the command can
+// see, by name or alias, including inherited ones.
+func (c *Command) validateFlagReplacements() error {
+	var errs []error
+	c.walk(func(command *Command) {
+		for _, flag := range command.flags {
+			if flag.replacedBy == "" {
+				continue
+			}
+
+			if _, found := command.findLongFlag(flag.replacedBy); !found {
+				errs = append(errs, errors.Errorf("flag %q of %q replaced by unknown flag %q", flag.name, command.qualifiedName(), flag.replacedBy))
+			}
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+func (c *Command) isHiddenUpToRoot() bool {
+	return c.isHidden || (c.hasParent() && c.parent.isHiddenUpToRoot())
+}
+
+func (c *Command) visibleSubCommands() []*Command {
+	return lo.Reject(c.subCommands, func(subCommand *Command, _ int) bool { return subCommand.isHidden })
+}
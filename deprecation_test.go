@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Run_deprecation(t *testing.T) {
+	type TestCase struct {
+		rawArgs          []string
+		expectedRan      string
+		expectedOutput   string
+		expectedTarget   string
+		expectedWarnings string
+	}
+
+	testCases := map[string]TestCase{
+		"current names": {
+			rawArgs:        []string{"remove", "--output", "out.txt", "target"},
+			expectedRan:    "remove",
+			expectedOutput: "out.txt",
+			expectedTarget: "target",
+		},
+		"deprecated flag": {
+			rawArgs:          []string{"remove", "--out", "out.txt", "target"},
+			expectedRan:      "remove",
+			expectedOutput:   "out.txt",
+			expectedTarget:   "target",
+			expectedWarnings: "warning: flag \"--out\" is deprecated: use --output instead\n",
+		},
+		"deprecated flag with equal": {
+			rawArgs:          []string{"remove", "--out=out.txt", "target"},
+			expectedRan:      "remove",
+			expectedOutput:   "out.txt",
+			expectedTarget:   "target",
+			expectedWarnings: "warning: flag \"--out\" is deprecated: use --output instead\n",
+		},
+		"deprecated short flag": {
+			rawArgs:          []string{"remove", "-O", "out.txt", "target"},
+			expectedRan:      "remove",
+			expectedOutput:   "out.txt",
+			expectedTarget:   "target",
+			expectedWarnings: "warning: flag \"-O\" is deprecated: use --output instead\n",
+		},
+		"deprecated command": {
+			rawArgs:          []string{"rm", "target"},
+			expectedRan:      "remove",
+			expectedTarget:   "target",
+			expectedWarnings: "warning: command \"test rm\" is deprecated: use remove instead\n",
+		},
+		"deprecated argument": {
+			rawArgs:          []string{"remove", "target", "force"},
+			expectedRan:      "remove",
+			expectedTarget:   "target",
+			expectedWarnings: "warning: argument \"mode\" is deprecated: use --force instead\n",
+		},
+		"hidden command": {
+			rawArgs:     []string{"debug"},
+			expectedRan: "debug",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var ran string
+			handler := func(name string) Handler {
+				return func(ctx context.Context) error {
+					ran = name
+					if name != "remove" {
+						return nil
+					}
+
+					output, err := FlagValue[string](ctx, "output")
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expectedOutput, output)
+
+					target, err := ArgValue[string](ctx, "target")
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expectedTarget, target)
+					return nil
+				}
+			}
+
+			command, err := NewCommand("test", "test command",
+				AddSubCmd("remove", "remove a target",
+					SetHandler(handler("remove")),
+					AddFlag("output", "output file"),
+					AddFlag("out", "output file", AddFlagShort('O'), SetFlagDeprecated("use --output instead"), SetFlagReplacedBy("output")),
+					AddArg("target", "target"),
+					AddArg("mode", "mode", SetArgDefault(""), SetArgDeprecated("use --force instead")),
+				),
+				AddSubCmd("rm", "remove a target", SetHandler(handler("rm")), SetCommandDeprecated("use remove instead"), SetCommandReplacedBy("remove")),
+				AddSubCmd("debug", "debug internals", SetHandler(handler("debug")), SetCommandIsHidden(true)),
+			)
+			require.NoError(t, err)
+
+			warnings := captureStderr(t, func() {
+				require.NoError(t, command.Run(context.TODO(), testCase.rawArgs))
+			})
+
+			assert.Equal(t, testCase.expectedRan, ran)
+			assert.Equal(t, testCase.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestCommand_Run_replacedBy(t *testing.T) {
+	t.Run("aliases and inherited flags", func(t *testing.T) {
+		var output string
+		command, err := NewCommand("test", "test command",
+			AddFlag("output", "output file", AddFlagAlias("out-file"), SetFlagIsInherited(true)),
+			AddSubCmd("remove", "remove a target",
+				AddAlias("delete"),
+				AddFlag("dest", "output file", SetFlagReplacedBy("out-file")),
+				SetHandler(func(ctx context.Context) error {
+					var err error
+					output, err = FlagValue[string](ctx, "output")
+					return err
+				}),
+			),
+			AddSubCmd("rm", "remove a target", SetCommandReplacedBy("delete")),
+		)
+
+		require.NoError(t, err)
+		require.NoError(t, command.Run(context.TODO(), []string{"rm", "--dest", "out.txt"}))
+		assert.Equal(t, "out.txt", output)
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("out", "output", SetFlagReplacedBy("output")),
+			SetHandler(func(context.Context) error { return nil }),
+		)
+
+		require.NoError(t, err)
+		assert.EqualError(t, command.Run(context.TODO(), nil), `invalid command "test": flag "out" of "test" replaced by unknown flag "output"`)
+	})
+}
+
+func TestCommand_renderHelp_hidden(t *testing.T) {
+	command, err := NewCommand("test", "test command",
+		AddFlag("output", "output file"),
+		AddFlag("out", "output file", SetFlagDeprecated("use --output instead"), SetFlagReplacedBy("output")),
+		AddSubCmd("remove", "remove a target", AddArg("mode", "mode", SetArgDeprecated("use --force instead"))),
+		AddSubCmd("rm", "remove a target", SetCommandDeprecated("use remove instead"), SetCommandReplacedBy("remove")),
+		AddSubCmd("debug", "debug internals", SetCommandIsHidden(true)),
+	)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, command.renderHelp(buffer))
+	assert.Equal(t, heredoc.Doc(`
+		test: test command
+
+		Usage:
+		  test [flags] [sub-command]
+
+		Sub-commands:
+		  remove: remove a target
+
+		Flags:
+		  --output    output file  (type: string, default: "")
+
+	`), buffer.String())
+
+	buffer.Reset()
+	require.NoError(t, command.subCommands[0].renderHelp(buffer))
+	assert.Contains(t, buffer.String(), `<mode>  mode  (type: string, deprecated: use --force instead)`)
+
+	err = command.Run(context.TODO(), []string{"debg"})
+	assert.EqualError(t, err, `"debg" for "test": unknown sub-command`)
+
+	dir := t.TempDir()
+	require.NoError(t, command.WriteMarkdownDocs(dir))
+	assert.NoFileExists(t, dir+"/test-debug.md")
+	assert.FileExists(t, dir+"/test-remove.md")
+}
+
+func captureStderr(t *testing.T, f func()) string {
+	stderr := os.Stderr
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stderr = writer
+	t.Cleanup(func() { os.Stderr = stderr })
+
+	f()
+	require.NoError(t, writer.Close())
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(output)
+}
@@ -1,14 +1,21 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/bobg/errors"
 	"github.com/broothie/option"
 	"github.com/samber/lo"
 )
 
-const helpFlagName = "help"
+const (
+	helpFlagName   = "help"
+	negationPrefix = "no-"
+)
+
+var ContradictoryFlagError = errors.New("contradictory flag values")
 
 type Flag struct {
 	name           string
@@ -17,13 +24,25 @@ type Flag struct {
 	shorts         []rune
 	isHelp         bool
 	isVersion      bool
+	isConfig       bool
+	isColor        bool
 	isHidden       bool
 	isInherited    bool
+	isNegatable    bool
+	isCounter      bool
+	isRequired     bool
 	parser         argParser
 	defaultEnvName string
 	defaultValue   any
-
-	value any
+	completer      Completer
+	choices        []string
+	choicesFunc    ChoicesFunc
+	group          string
+	deprecation    string
+	replacedBy     string
+
+	value      any
+	helpFormat string
 }
 
 func newFlag(name, description string, options ...option.Option[*Flag]) (*Flag, error) {
@@ -50,6 +69,136 @@ func (f *Flag) isBool() bool {
 	return isBoolParser(f.parser)
 }
 
+// flagValue resolves the value of the flag, in order of precedence, from the command line, its environment variable,
+// the config file, and finally its default.
+func (c *Command) flagValue(ctx context.Context, flag *Flag) (any, error) {
+	if flag.value != nil {
+		return flag.value, nil
+	}
+
+	if flag.defaultEnvName != "" {
+		if rawValue, found := os.LookupEnv(flag.defaultEnvName); found {
+			value, err := flag.parser.Parse(rawValue)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing $%s for flag %q", flag.defaultEnvName, flag.name)
+			}
+
+			if err := validateChoice(value, flag.allowedChoices(c.onContext(ctx))); err != nil {
+				return nil, errors.Wrapf(err, "parsing $%s for flag %q", flag.defaultEnvName, flag.name)
+			}
+
+			return value, nil
+		}
+	}
+
+	if configValue, found := c.configValue(flag); found {
+		value, err := flag.parseConfigValue(configValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing config value for flag %q", flag.name)
+		}
+
+		if err := validateChoice(value, flag.allowedChoices(c.onContext(ctx))); err != nil {
+			return nil, errors.Wrapf(err, "parsing config value for flag %q", flag.name)
+		}
+
+		return value, nil
+	}
+
+	return flag.defaultValue, nil
+}
+
+// isFlagSet reports whether the flag has a value from the command line, its environment variable, or the config file.
+func (c *Command) isFlagSet(flag *Flag) bool {
+	if flag.value != nil {
+		return true
+	}
+
+	if flag.defaultEnvName != "" {
+		if _, found := os.LookupEnv(flag.defaultEnvName); found {
+			return true
+		}
+	}
+
+	_, found := c.configValue(flag)
+	return found
+}
+
+// resetFlags clears the values parsed into the command's own flags, so repeatable flags and counters start over on
+// each parse instead of building on the last one.
+func (c *Command) resetFlags() {
+	for _, flag := range c.flags {
+		flag.value = nil
+		flag.helpFormat = ""
+	}
+}
+
+func (f *Flag) isRepeatable() bool {
+	_, isAccumulating := f.parser.(accumulatingArgParser)
+	return isAccumulating
+}
+
+func (f *Flag) setValue(ctx context.Context, rawValue string) error {
+	if f.isHelp && rawValue == helpFormatJSON {
+		f.helpFormat = rawValue
+		return f.assign(true)
+	}
+
+	value, err := parseValue(f.parser, f.value, rawValue)
+	if err != nil {
+		return err
+	}
+
+	if err := validateChoice(value, f.allowedChoices(ctx)); err != nil {
+		return err
+	}
+
+	return f.assign(value)
+}
+
+func (f *Flag) setNegatedValue(_ context.Context, rawValue string) error {
+	value, err := f.parser.Parse(rawValue)
+	if err != nil {
+		return err
+	}
+
+	return f.assign(!value.(bool))
+}
+
+func (f *Flag) setPresent(isNegated bool) error {
+	if f.isNegatable {
+		return f.assign(!isNegated)
+	}
+
+	return f.assign(!f.defaultValue.(bool))
+}
+
+func (f *Flag) increment() {
+	count, _ := f.value.(int)
+	f.value = count + 1
+}
+
+func (f *Flag) assign(value any) error {
+	if f.isNegatable && f.value != nil && f.value != value {
+		return errors.Wrapf(ContradictoryFlagError, "flags %q and %q", fmt.Sprintf("--%s", f.name), fmt.Sprintf("--%s%s", negationPrefix, f.name))
+	}
+
+	f.value = value
+	return nil
+}
+
+func (f *Flag) longNames() []string {
+	names := append([]string{f.name}, f.aliases...)
+	if !f.isNegatable {
+		return names
+	}
+
+	return append(names, lo.Map(names, func(name string, _ int) string { return negationPrefix + name })...)
+}
+
+func (f *Flag) takesValue() bool {
+	return !f.isBool() && !f.isCounter
+}
+
 func (c *Command) findFlag(name string) (*Flag, bool) {
 	return c.findFlagUpToRoot(func(flag *Flag) bool { return flag.name == name })
 }
@@ -95,3 +244,8 @@ func isBoolParser(parser argParser) bool {
 	_, isBool := parser.Type().(bool)
 	return isBool
 }
+
+func isIntParser(parser argParser) bool {
+	_, isInt := parser.Type().(int)
+	return isInt
+}
@@ -15,5 +15,13 @@ func (f *Flag) validateConfig() error {
 		return errors.New("flag name cannot be empty")
 	}
 
+	if f.isCounter && !isIntParser(f.parser) {
+		return errors.Errorf("counter flag %q must be an int", f.name)
+	}
+
+	if f.isNegatable && !f.isBool() {
+		return errors.Errorf("negatable flag %q must be a bool", f.name)
+	}
+
 	return nil
 }
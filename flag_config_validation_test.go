@@ -19,6 +19,18 @@ func TestFlag_validateConfig(t *testing.T) {
 		assert.EqualError(t, err, `flag name "invalid flag name" must be a single token`)
 	})
 
+	t.Run("counter non-int", func(t *testing.T) {
+		flag := &Flag{name: "valid-flag", isCounter: true, parser: NewArgParser(StringParser)}
+		err := flag.validateConfig()
+		assert.EqualError(t, err, `counter flag "valid-flag" must be an int`)
+	})
+
+	t.Run("negatable non-bool", func(t *testing.T) {
+		flag := &Flag{name: "valid-flag", isNegatable: true, parser: NewArgParser(StringParser)}
+		err := flag.validateConfig()
+		assert.EqualError(t, err, `negatable flag "valid-flag" must be a bool`)
+	})
+
 	t.Run("valid name", func(t *testing.T) {
 		flag := &Flag{name: "valid-flag"}
 		err := flag.validateConfig()
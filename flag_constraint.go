@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+var (
+	MutuallyExclusiveFlagsError = errors.New("mutually exclusive flags")
+	FlagsRequiredTogetherError  = errors.New("flags required together")
+)
+
+type flagConstraint interface {
+	flagNames() []string
+	validateInput(ctx context.Context, command *Command) error
+	help() FlagConstraintHelp
+}
+
+type mutuallyExclusiveFlags []string
+
+func (m mutuallyExclusiveFlags) flagNames() []string {
+	return m
+}
+
+func (m mutuallyExclusiveFlags) validateInput(ctx context.Context, command *Command) error {
+	set := command.setFlagNames(m)
+	if len(set) < 2 {
+		return nil
+	}
+
+	return errors.Wrapf(MutuallyExclusiveFlagsError, "flags %s", quotedFlagNames(set))
+}
+
+func (m mutuallyExclusiveFlags) help() FlagConstraintHelp {
+	return FlagConstraintHelp{Flags: m, Description: "mutually exclusive"}
+}
+
+type flagsRequiredTogether []string
+
+func (f flagsRequiredTogether) flagNames() []string {
+	return f
+}
+
+func (f flagsRequiredTogether) validateInput(ctx context.Context, command *Command) error {
+	set := command.setFlagNames(f)
+	if len(set) == 0 || len(set) == len(f) {
+		return nil
+	}
+
+	missing, _ := lo.Difference(f, set)
+	return errors.Wrapf(FlagsRequiredTogetherError, "%s requires %s", quotedFlagNames(set), quotedFlagNames(missing))
+}
+
+func (f flagsRequiredTogether) help() FlagConstraintHelp {
+	return FlagConstraintHelp{Flags: f, Description: "required together"}
+}
+
+type flagRequiredIf struct {
+	name           string
+	conditionName  string
+	conditionValue any
+}
+
+func (f flagRequiredIf) flagNames() []string {
+	return []string{f.name, f.conditionName}
+}
+
+func (f flagRequiredIf) validateInput(ctx context.Context, command *Command) error {
+	flag, found := command.findFlag(f.name)
+	if !found {
+		return errors.Wrapf(FlagNotFoundError, "flag constraint refers to %s", quotedFlagNames([]string{f.name}))
+	}
+
+	conditionFlag, found := command.findFlag(f.conditionName)
+	if !found {
+		return errors.Wrapf(FlagNotFoundError, "flag constraint refers to %s", quotedFlagNames([]string{f.conditionName}))
+	}
+
+	conditionValue, err := command.flagValue(ctx, conditionFlag)
+	if err != nil {
+		return err
+	}
+
+	if fmt.Sprint(conditionValue) != fmt.Sprint(f.conditionValue) || command.isFlagSet(flag) {
+		return nil
+	}
+
+	return errors.Wrapf(FlagMissingValueError, "flag %s is required when %s is %q", quotedFlagNames([]string{f.name}), quotedFlagNames([]string{f.conditionName}), fmt.Sprint(f.conditionValue))
+}
+
+func (f flagRequiredIf) help() FlagConstraintHelp {
+	return FlagConstraintHelp{Flags: []string{f.name}, Description: fmt.Sprintf("required when --%s=%v", f.conditionName, f.conditionValue)}
+}
+
+// flagConstraintsUpToRoot returns the command's own flag constraints, plus those of its ancestors whose flags are all
+// inherited by the command.
+func (c *Command) flagConstraintsUpToRoot() []flagConstraint {
+	constraints := c.flagConstraints
+	for current := c.parent; current != nil; current = current.parent {
+		constraints = append(constraints, lo.Filter(current.flagConstraints, func(constraint flagConstraint, _ int) bool {
+			return lo.EveryBy(constraint.flagNames(), func(name string) bool {
+				flag, found := c.findFlag(name)
+				ancestorFlag, ancestorFound := current.findFlag(name)
+				return found && ancestorFound && flag == ancestorFlag
+			})
+		})...)
+	}
+
+	return constraints
+}
+
+func (c *Command) setFlagNames(names []string) []string {
+	return lo.Filter(names, func(name string, _ int) bool {
+		flag, found := c.findFlag(name)
+		return found && c.isFlagSet(flag)
+	})
+}
+
+func dashedFlagNames(names []string) string {
+	return strings.Join(lo.Map(names, func(name string, _ int) string { return fmt.Sprintf("--%s", name) }), ", ")
+}
+
+func quotedFlagNames(names []string) string {
+	return strings.Join(lo.Map(names, func(name string, _ int) string { return fmt.Sprintf("%q", fmt.Sprintf("--%s", name)) }), ", ")
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bobg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_flagConstraints(t *testing.T) {
+	type TestCase struct {
+		rawArgs       []string
+		expectedError string
+	}
+
+	testCases := map[string]TestCase{
+		"none set":                    {},
+		"one exclusive flag":          {rawArgs: []string{"--json"}},
+		"both exclusive flags":        {rawArgs: []string{"--json", "--table"}, expectedError: `flags "--json", "--table": mutually exclusive flags`},
+		"required together":           {rawArgs: []string{"--cert", "cert.pem", "--key", "key.pem"}},
+		"missing required together":   {rawArgs: []string{"--cert", "cert.pem"}, expectedError: `"--cert" requires "--key": flags required together`},
+		"required if condition unmet": {rawArgs: []string{"--auth", "token"}},
+		"required if condition met":   {rawArgs: []string{"--auth", "basic", "--password", "hunter2"}},
+		"missing required if":         {rawArgs: []string{"--auth", "basic"}, expectedError: `flag "--password" is required when "--auth" is "basic": flag missing value`},
+		"aggregated": {
+			rawArgs:       []string{"--json", "--table", "--key", "key.pem"},
+			expectedError: "flags \"--json\", \"--table\": mutually exclusive flags\n\"--key\" requires \"--cert\": flags required together",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, err := NewCommand("test", "test command",
+				AddFlag("json", "json output", SetFlagDefault(false)),
+				AddFlag("table", "table output", SetFlagDefault(false)),
+				AddFlag("cert", "certificate"),
+				AddFlag("key", "key"),
+				AddFlag("auth", "auth method", SetFlagDefault("none")),
+				AddFlag("password", "password"),
+				AddMutuallyExclusiveFlags("json", "table"),
+				AddFlagsRequiredTogether("cert", "key"),
+				AddFlagRequiredIf("password", "auth", "basic"),
+				SetHandler(func(context.Context) error { return nil }),
+			)
+
+			require.NoError(t, err)
+
+			err = command.Run(context.TODO(), testCase.rawArgs)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("env satisfies", func(t *testing.T) {
+		t.Setenv("TEST_KEY", "key.pem")
+
+		command, err := NewCommand("test", "test command",
+			AddFlag("cert", "certificate"),
+			AddFlag("key", "key", SetFlagDefaultEnv("TEST_KEY")),
+			AddFlagsRequiredTogether("cert", "key"),
+			SetHandler(func(context.Context) error { return nil }),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), []string{"--cert", "cert.pem"}))
+	})
+
+	t.Run("inherited flags", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddSubCmd("list", "list things",
+				AddFlag("table", "table output", SetFlagDefault(false)),
+				AddMutuallyExclusiveFlags("json", "table"),
+				SetHandler(func(context.Context) error { return nil }),
+			),
+		)
+
+		require.NoError(t, err)
+		assert.EqualError(t, command.Run(context.TODO(), []string{"--json", "list", "--table"}), `flags "--json", "--table": mutually exclusive flags`)
+	})
+
+	t.Run("ancestor constraint on inherited flags", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddFlag("table", "table output", SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddMutuallyExclusiveFlags("json", "table"),
+			AddSubCmd("list", "list things", SetHandler(func(context.Context) error { return nil })),
+		)
+
+		require.NoError(t, err)
+		assert.EqualError(t, command.Run(context.TODO(), []string{"list", "--json", "--table"}), `flags "--json", "--table": mutually exclusive flags`)
+	})
+
+	t.Run("ancestor constraint on shadowed flags", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddFlag("table", "table output", SetFlagDefault(false)),
+			AddMutuallyExclusiveFlags("json", "table"),
+			AddSubCmd("list", "list things",
+				AddFlag("table", "table output", SetFlagDefault(false)),
+				SetHandler(func(context.Context) error { return nil }),
+			),
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, command.Run(context.TODO(), []string{"list", "--json", "--table"}))
+	})
+
+	t.Run("required if on a non-root command", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddSubCmd("list", "list things",
+				AddFlag("template", "output template"),
+				AddFlagRequiredIf("template", "format", "custom"),
+				SetHandler(func(context.Context) error { return nil }),
+			),
+		)
+
+		require.NoError(t, err)
+		assert.True(t, errors.Is(command.subCommands[0].Run(context.TODO(), []string{"--template", "x"}), FlagNotFoundError))
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false)),
+			AddMutuallyExclusiveFlags("json", "table"),
+			SetHandler(func(context.Context) error { return nil }),
+		)
+
+		require.NoError(t, err)
+		assert.EqualError(t, command.Run(context.TODO(), nil), `invalid command "test": flag constraint of "test" refers to unknown flag "table"`)
+	})
+
+	t.Run("unknown flag in sub-command", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false)),
+			AddSubCmd("list", "list things",
+				AddFlag("table", "table output", SetFlagDefault(false)),
+				AddMutuallyExclusiveFlags("json", "table"),
+			),
+		)
+
+		require.NoError(t, err)
+		assert.EqualError(t, command.validateTree(), `invalid command "test": flag constraint of "test list" refers to unknown flag "json"`)
+	})
+
+	t.Run("sentinel", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false)),
+			AddFlag("table", "table output", SetFlagDefault(false)),
+			AddMutuallyExclusiveFlags("json", "table"),
+			SetHandler(func(context.Context) error { return nil }),
+		)
+
+		require.NoError(t, err)
+		assert.True(t, errors.Is(command.Run(context.TODO(), []string{"--json", "--table"}), MutuallyExclusiveFlagsError))
+	})
+}
@@ -0,0 +1,13 @@
+package cli
+
+import "github.com/bobg/errors"
+
+var FlagMissingValueError = errors.New("flag missing value")
+
+func (f *Flag) validateInput(command *Command) error {
+	if f.isRequired && !command.isFlagSet(f) {
+		return errors.Wrapf(FlagMissingValueError, "flag %q", f.name)
+	}
+
+	return nil
+}
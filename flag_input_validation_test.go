@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlag_validateInput(t *testing.T) {
+	command, err := NewCommand("test", "test command",
+		AddFlag("token", "API token", SetFlagRequired(true), SetFlagDefaultEnv("TEST_TOKEN")),
+	)
+
+	require.NoError(t, err)
+	flag := command.flags[0]
+
+	assert.EqualError(t, flag.validateInput(command), `flag "token": flag missing value`)
+
+	t.Setenv("TEST_TOKEN", "secret")
+	assert.NoError(t, flag.validateInput(command))
+}
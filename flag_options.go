@@ -26,6 +26,33 @@ func SetFlagIsHidden(isHidden bool) option.Func[*Flag] {
 	}
 }
 
+// SetFlagGroup sets the heading the flag is listed under in help.
+func SetFlagGroup(group string) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.group = group
+		return flag, nil
+	}
+}
+
+// SetFlagDeprecated hides the flag and makes passing it print a warning with deprecation to stderr,
+// e.g. "use --output instead".
+func SetFlagDeprecated(deprecation string) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.deprecation = deprecation
+		flag.isHidden = true
+		return flag, nil
+	}
+}
+
+// SetFlagReplacedBy forwards values passed to the flag to the flag with the given name or alias, defined on the same
+// command or inherited, so a renamed flag keeps working under its old name.
+func SetFlagReplacedBy(name string) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.replacedBy = name
+		return flag, nil
+	}
+}
+
 // SetFlagIsInherited controls whether the flag is inherited by child commands.
 func SetFlagIsInherited(isInherited bool) option.Func[*Flag] {
 	return func(flag *Flag) (*Flag, error) {
@@ -34,6 +61,35 @@ func SetFlagIsInherited(isInherited bool) option.Func[*Flag] {
 	}
 }
 
+// SetFlagRequired controls whether the flag must be given a value, either on the command line, through its environment
+// variable, or in the config file.
+func SetFlagRequired(isRequired bool) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.isRequired = isRequired
+		return flag, nil
+	}
+}
+
+// SetFlagNegatable controls whether the bool flag can be negated with a "--no-" prefix, e.g. "--no-color".
+// A negatable flag is set to true when passed, rather than to the opposite of its default.
+func SetFlagNegatable(isNegatable bool) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.isNegatable = isNegatable
+		return flag, nil
+	}
+}
+
+// SetFlagCounter makes the flag count its occurrences, so "-vvv", "-v -v -v" and "--verbose --verbose --verbose" all
+// set it to 3. Its value is read with FlagValue[int].
+func SetFlagCounter() option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.isCounter = true
+		flag.parser = NewArgParser(IntParser)
+		flag.defaultValue = 0
+		return flag, nil
+	}
+}
+
 // SetFlagDefault sets the default value of the flag.
 func SetFlagDefault[T Parseable](defaultValue T) option.Func[*Flag] {
 	return func(flag *Flag) (*Flag, error) {
@@ -58,6 +114,8 @@ func SetFlagDefaultAndParser[T any](defaultValue T, argParser ArgParser[T]) opti
 }
 
 // SetFlagDefaultEnv sets the default value to that of the corresponding environment variable, and parser of the flag.
+// A variable which is set, even to an empty string, takes precedence over the config file and default value; an unset
+// variable falls through to them.
 func SetFlagDefaultEnv(name string) option.Func[*Flag] {
 	return func(flag *Flag) (*Flag, error) {
 		flag.defaultEnvName = name
@@ -65,6 +123,38 @@ func SetFlagDefaultEnv(name string) option.Func[*Flag] {
 	}
 }
 
+// SetFlagCompleter sets the function used to complete values of the flag.
+func SetFlagCompleter(completer Completer) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.completer = completer
+		return flag, nil
+	}
+}
+
+// SetFlagChoices restricts the values of the flag to the given choices.
+func SetFlagChoices(choices ...string) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.choices = append(flag.choices, choices...)
+		return flag, nil
+	}
+}
+
+// SetFlagChoicesFunc restricts the values of the flag to those returned by choicesFunc, evaluated when the flag is parsed.
+func SetFlagChoicesFunc(choicesFunc ChoicesFunc) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.choicesFunc = choicesFunc
+		return flag, nil
+	}
+}
+
+func setFlagParserAndDefault(parser argParser, defaultValue any) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.parser = parser
+		flag.defaultValue = defaultValue
+		return flag, nil
+	}
+}
+
 func setFlagIsHelp(isHelp bool) option.Func[*Flag] {
 	return func(flag *Flag) (*Flag, error) {
 		flag.isHelp = isHelp
@@ -78,3 +168,17 @@ func setFlagIsVersion(isVersion bool) option.Func[*Flag] {
 		return flag, nil
 	}
 }
+
+func setFlagIsColor(isColor bool) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.isColor = isColor
+		return flag, nil
+	}
+}
+
+func setFlagIsConfig(isConfig bool) option.Func[*Flag] {
+	return func(flag *Flag) (*Flag, error) {
+		flag.isConfig = isConfig
+		return flag, nil
+	}
+}
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,3 +26,341 @@ func TestCommand_flagsUpToRoot(t *testing.T) {
 	assert.Contains(t, flagNames, "flag")
 	assert.NotContains(t, flagNames, "top-uninherited")
 }
+
+func TestFlag_repeatable(t *testing.T) {
+	type TestCase struct {
+		rawArgs          []string
+		expectedIncludes []string
+		expectedTags     []int
+		expectedLabels   map[string]string
+	}
+
+	testCases := map[string]TestCase{
+		"defaults": {
+			expectedIncludes: []string{"."},
+			expectedTags:     []int{},
+			expectedLabels:   map[string]string{},
+		},
+		"repeated": {
+			rawArgs:          []string{"-I", "a", "--include", "b", "-I=c", "--include=d"},
+			expectedIncludes: []string{"a", "b", "c", "d"},
+			expectedTags:     []int{},
+			expectedLabels:   map[string]string{},
+		},
+		"comma separated": {
+			rawArgs:          []string{"--tag=1,2", "--tag", "3"},
+			expectedIncludes: []string{"."},
+			expectedTags:     []int{1, 2, 3},
+			expectedLabels:   map[string]string{},
+		},
+		"map": {
+			rawArgs:          []string{"--label", "env=prod", "--label=team=infra,tier=web"},
+			expectedIncludes: []string{"."},
+			expectedTags:     []int{},
+			expectedLabels:   map[string]string{"env": "prod", "team": "infra", "tier": "web"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, err := NewCommand("test", "test command",
+				AddFlag("include", "include", AddFlagShort('I'), SetFlagDefault([]string{"."})),
+				AddFlag("tag", "tag", SetFlagDefault([]int{})),
+				AddFlag("label", "label", SetFlagDefault(map[string]string{})),
+				SetHandler(func(ctx context.Context) error {
+					includes, err := FlagValue[[]string](ctx, "include")
+					assert.NoError(t, err)
+					assert.Equal(t, testCase.expectedIncludes, includes)
+
+					tags, err := FlagValue[[]int](ctx, "tag")
+					assert.NoError(t, err)
+					assert.Equal(t, testCase.expectedTags, tags)
+
+					labels, err := FlagValue[map[string]string](ctx, "label")
+					assert.NoError(t, err)
+					assert.Equal(t, testCase.expectedLabels, labels)
+
+					return nil
+				}),
+			)
+
+			assert.NoError(t, err)
+			assert.NoError(t, command.Run(context.TODO(), testCase.rawArgs))
+		})
+	}
+
+	t.Run("each run starts over", func(t *testing.T) {
+		var tags []string
+		command, err := NewCommand("test", "test command",
+			AddFlag("tag", "tag", SetFlagDefault([]string{})),
+			SetHandler(func(ctx context.Context) error {
+				var err error
+				tags, err = FlagValue[[]string](ctx, "tag")
+				return err
+			}),
+		)
+
+		assert.NoError(t, err)
+
+		assert.NoError(t, command.Run(context.TODO(), []string{"--tag", "x"}))
+		assert.Equal(t, []string{"x"}, tags)
+
+		assert.NoError(t, command.Run(context.TODO(), []string{"--tag", "y"}))
+		assert.Equal(t, []string{"y"}, tags)
+	})
+}
+
+func TestFlag_negatable(t *testing.T) {
+	type TestCase struct {
+		rawArgs       []string
+		expectedColor bool
+		expectedError string
+	}
+
+	testCases := map[string]TestCase{
+		"default":              {expectedColor: true},
+		"long":                 {rawArgs: []string{"--color"}, expectedColor: true},
+		"negated":              {rawArgs: []string{"--no-color"}, expectedColor: false},
+		"negated alias":        {rawArgs: []string{"--no-colour"}, expectedColor: false},
+		"short":                {rawArgs: []string{"-c"}, expectedColor: true},
+		"long with value":      {rawArgs: []string{"--color=false"}, expectedColor: false},
+		"short with value":     {rawArgs: []string{"-c=false"}, expectedColor: false},
+		"negated with value":   {rawArgs: []string{"--no-color=false"}, expectedColor: true},
+		"repeated":             {rawArgs: []string{"--no-color", "--color=false"}, expectedColor: false},
+		"contradictory":        {rawArgs: []string{"--color", "--no-color"}, expectedError: `flags "--color" and "--no-color": contradictory flag values`},
+		"contradictory values": {rawArgs: []string{"-c", "--color=false"}, expectedError: `parsing provided value "false" for flag "--color": flags "--color" and "--no-color": contradictory flag values`},
+		"not negatable":        {rawArgs: []string{"--no-verbose"}, expectedError: `no flag found for "--no-verbose": invalid flag`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, err := NewCommand("test", "test command",
+				AddFlag("color", "colorize output",
+					AddFlagAlias("colour"),
+					AddFlagShort('c'),
+					SetFlagDefault(true),
+					SetFlagNegatable(true),
+				),
+				AddFlag("verbose", "be verbose", SetFlagDefault(false)),
+				SetHandler(func(ctx context.Context) error {
+					color, err := FlagValue[bool](ctx, "color")
+					assert.NoError(t, err)
+					assert.Equal(t, testCase.expectedColor, color)
+
+					return nil
+				}),
+			)
+
+			assert.NoError(t, err)
+
+			err = command.Run(context.TODO(), testCase.rawArgs)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFlag_boolWithValue(t *testing.T) {
+	for _, rawArgs := range [][]string{{"--all=false"}, {"-a=false"}, {"--all=true", "-a=false"}} {
+		t.Run(strings.Join(rawArgs, " "), func(t *testing.T) {
+			command, err := NewCommand("test", "test command",
+				AddFlag("all", "all", AddFlagShort('a'), SetFlagDefault(true)),
+				SetHandler(func(ctx context.Context) error {
+					all, err := FlagValue[bool](ctx, "all")
+					assert.NoError(t, err)
+					assert.False(t, all)
+
+					return nil
+				}),
+			)
+
+			assert.NoError(t, err)
+			assert.NoError(t, command.Run(context.TODO(), rawArgs))
+		})
+	}
+}
+
+func TestFlag_counter(t *testing.T) {
+	testCases := map[string]struct {
+		rawArgs         []string
+		expectedVerbose int
+	}{
+		"default":            {expectedVerbose: 0},
+		"short group":        {rawArgs: []string{"-vvv"}, expectedVerbose: 3},
+		"mixed with bool":    {rawArgs: []string{"-vqv"}, expectedVerbose: 2},
+		"separate shorts":    {rawArgs: []string{"-v", "-v", "-v"}, expectedVerbose: 3},
+		"long":               {rawArgs: []string{"--verbose", "--verbose"}, expectedVerbose: 2},
+		"explicit value":     {rawArgs: []string{"--verbose=5"}, expectedVerbose: 5},
+		"inherited":          {rawArgs: []string{"-v", "sub", "-vv"}, expectedVerbose: 3},
+		"inherited explicit": {rawArgs: []string{"sub", "-v=2", "-v"}, expectedVerbose: 3},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			called := ensureCalled(t)
+			handler := func(ctx context.Context) error {
+				called()
+
+				verbose, err := FlagValue[int](ctx, "verbose")
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedVerbose, verbose)
+
+				return nil
+			}
+
+			command, err := NewCommand("test", "test command",
+				AddFlag("verbose", "verbosity", AddFlagShort('v'), SetFlagCounter(), SetFlagIsInherited(true)),
+				AddFlag("quiet", "quiet", AddFlagShort('q'), SetFlagDefault(false)),
+				AddSubCmd("sub", "sub-command", SetHandler(handler)),
+				SetHandler(handler),
+			)
+
+			assert.NoError(t, err)
+			assert.NoError(t, command.Run(context.TODO(), testCase.rawArgs))
+		})
+	}
+
+	t.Run("each run starts over", func(t *testing.T) {
+		var verbose int
+		command, err := NewCommand("test", "test command",
+			AddFlag("verbose", "verbosity", AddFlagShort('v'), SetFlagCounter()),
+			SetHandler(func(ctx context.Context) error {
+				var err error
+				verbose, err = FlagValue[int](ctx, "verbose")
+				return err
+			}),
+		)
+
+		assert.NoError(t, err)
+
+		assert.NoError(t, command.Run(context.TODO(), []string{"--verbose"}))
+		assert.Equal(t, 1, verbose)
+
+		assert.NoError(t, command.Run(context.TODO(), []string{"--verbose"}))
+		assert.Equal(t, 1, verbose)
+	})
+}
+
+func TestFlag_choices(t *testing.T) {
+	testCases := map[string]struct {
+		rawArgs        []string
+		env            string
+		expectedFormat string
+		expectedTags   []string
+		expectedError  string
+	}{
+		"default":          {expectedFormat: "table"},
+		"valid":            {rawArgs: []string{"--format", "json"}, expectedFormat: "json"},
+		"valid with equal": {rawArgs: []string{"--format=yaml"}, expectedFormat: "yaml"},
+		"dynamic":          {rawArgs: []string{"--format", "csv"}, expectedFormat: "csv"},
+		"repeatable":       {rawArgs: []string{"--tag", "a", "--tag", "b"}, expectedFormat: "table", expectedTags: []string{"a", "b"}},
+		"suggestion": {
+			rawArgs:       []string{"--format", "jsn"},
+			expectedError: `parsing provided value "jsn" for flag "--format": "jsn" is not one of json|yaml|table|csv (did you mean "json"?): invalid choice`,
+		},
+		"no suggestion": {
+			rawArgs:       []string{"-f", "xlsx"},
+			expectedError: `parsing provided value "xlsx" for flag "-f": "xlsx" is not one of json|yaml|table|csv: invalid choice`,
+		},
+		"invalid repeatable element": {
+			rawArgs:       []string{"--tag", "a", "--tag", "c"},
+			expectedError: `parsing provided value "c" for flag "--tag": "c" is not one of a|b (did you mean "a"?): invalid choice`,
+		},
+		"dynamic env": {env: "csv", expectedFormat: "csv"},
+		"invalid env": {
+			env:           "xml",
+			expectedError: `parsing $TEST_FORMAT for flag "format": "xml" is not one of json|yaml|table|csv (did you mean "yaml"?): invalid choice`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if testCase.env != "" {
+				t.Setenv("TEST_FORMAT", testCase.env)
+			}
+
+			command, err := NewCommand("test", "test command",
+				AddFlag("format", "output format",
+					AddFlagShort('f'),
+					SetFlagDefault("table"),
+					SetFlagDefaultEnv("TEST_FORMAT"),
+					SetFlagChoices("json", "yaml", "table"),
+					SetFlagChoicesFunc(func(context.Context) []string { return []string{"csv"} }),
+				),
+				AddFlag("tag", "tags", SetFlagDefault([]string{}), SetFlagChoices("a", "b")),
+				SetHandler(func(ctx context.Context) error {
+					format, err := FlagValue[string](ctx, "format")
+					if err != nil {
+						return err
+					}
+
+					tags, err := FlagValue[[]string](ctx, "tag")
+					if err != nil {
+						return err
+					}
+
+					assert.Equal(t, testCase.expectedFormat, format)
+					assert.Equal(t, testCase.expectedTags, lo.Ternary(len(tags) == 0, nil, tags))
+					return nil
+				}),
+			)
+
+			assert.NoError(t, err)
+
+			err = command.Run(context.TODO(), testCase.rawArgs)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFlag_required(t *testing.T) {
+	testCases := map[string]struct {
+		rawArgs       []string
+		env           string
+		config        string
+		expectedError string
+	}{
+		"command line": {rawArgs: []string{"sub", "--token", "abc"}},
+		"env":          {rawArgs: []string{"sub"}, env: "abc"},
+		"config":       {rawArgs: []string{"sub"}, config: `{"token": "abc"}`},
+		"missing":      {rawArgs: []string{"sub"}, expectedError: `flag "token": flag missing value`},
+		"help":         {rawArgs: []string{"sub", "--help"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			if testCase.env != "" {
+				t.Setenv("TEST_TOKEN", testCase.env)
+			}
+
+			rawArgs := testCase.rawArgs
+			if testCase.config != "" {
+				rawArgs = append([]string{"--config", writeConfigFile(t, t.TempDir(), "config.json", testCase.config)}, rawArgs...)
+			}
+
+			command, err := NewCommand("test", "test command",
+				AddHelpFlag(SetFlagIsInherited(true)),
+				AddConfigFlag(),
+				AddFlag("token", "API token", SetFlagRequired(true), SetFlagDefaultEnv("TEST_TOKEN"), SetFlagIsInherited(true)),
+				AddSubCmd("sub", "sub-command", SetHandler(func(ctx context.Context) error { return nil })),
+			)
+
+			assert.NoError(t, err)
+
+			err = command.Run(context.TODO(), rawArgs)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
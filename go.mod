@@ -3,16 +3,19 @@ module github.com/broothie/cli
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/MakeNowJust/heredoc/v2 v2.0.1
 	github.com/bobg/errors v1.1.0
 	github.com/broothie/option v0.1.0
 	github.com/samber/lo v1.49.1
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/term v0.40.0
+	golang.org/x/text v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/sys v0.41.0 // indirect
 )
@@ -2,144 +2,475 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"fmt"
 	"io"
 	"strings"
-	"text/tabwriter"
 	"text/template"
 
 	"github.com/bobg/errors"
 	"github.com/samber/lo"
 )
 
+const tablePadding = 2
+
 //go:embed help.tmpl
 var rawHelpTemplate string
 
 var helpTemplate = template.Must(template.New("help").Parse(rawHelpTemplate))
 
-func (c *Command) helpContext() helpContext {
-	return helpContext{command: c}
+func (c *Command) helpData() HelpData {
+	return HelpData{command: c}
 }
 
+// HelpRenderer writes the help message for a command.
+type HelpRenderer func(w io.Writer, data HelpData) error
+
 func (c *Command) renderHelp(w io.Writer) error {
-	if err := helpTemplate.Execute(w, c.helpContext()); err != nil {
-		return errors.Wrap(err, "help template")
+	data := c.helpData()
+	data.theme = c.activeTheme(w)
+	data.width = c.findHelpWidth(w)
+
+	return c.findHelpRenderer()(w, data)
+}
+
+func (c *Command) findHelpRenderer() HelpRenderer {
+	for current := c; current != nil; current = current.parent {
+		if current.helpRenderer != nil {
+			return current.helpRenderer
+		}
 	}
 
-	return nil
+	return templateHelpRenderer(helpTemplate)
+}
+
+func templateHelpRenderer(parsedTemplate *template.Template) HelpRenderer {
+	return func(w io.Writer, data HelpData) error {
+		if err := parsedTemplate.Execute(w, data); err != nil {
+			return errors.Wrap(err, "help template")
+		}
+
+		return nil
+	}
 }
 
-type helpContext struct {
+// HelpData is the data available to help templates and renderers.
+type HelpData struct {
 	command *Command
+	theme   Theme
+	width   int
+}
+
+// CommandHelp describes a sub-command listed in help.
+type CommandHelp struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Group       string
+	Deprecated  string
+	ReplacedBy  string
+
+	command *Command
+}
+
+// FlagHelp describes a flag listed in help. DefinedBy is set for flags inherited from an ancestor.
+type FlagHelp struct {
+	FlagSchema
+
+	flag *Flag
+}
+
+// ArgumentHelp describes an argument listed in help.
+type ArgumentHelp struct {
+	ArgumentSchema
+
+	argument *Argument
+}
+
+// FlagConstraintHelp describes a flag constraint listed in help.
+type FlagConstraintHelp struct {
+	Flags       []string
+	Description string
+}
+
+// Theme is the active theme, which is unstyled when color is disabled.
+func (h HelpData) Theme() Theme {
+	return h.theme
+}
+
+// Width is the width tables are wrapped to, or 0 if they aren't wrapped.
+func (h HelpData) Width() int {
+	return h.width
+}
+
+// Heading renders a section heading in the theme's heading style.
+func (h HelpData) Heading(heading string) string {
+	return h.theme.Heading.Render(heading)
+}
+
+func (h HelpData) Name() string {
+	return h.command.name
+}
+
+func (h HelpData) Description() string {
+	return h.command.description
+}
+
+func (h HelpData) Aliases() []string {
+	return h.command.aliases
 }
 
-func (h helpContext) RootName() string {
+func (h HelpData) RootName() string {
 	return h.command.root().name
 }
 
-func (h helpContext) Version() string {
+func (h HelpData) Version() string {
 	return h.command.findVersion()
 }
 
-func (h helpContext) RootDescription() string {
+func (h HelpData) RootDescription() string {
 	return h.command.root().description
 }
 
-func (h helpContext) QualifiedName() string {
+func (h HelpData) QualifiedName() string {
 	return h.command.qualifiedName()
 }
 
-func (h helpContext) SubCommands() []*Command {
-	return h.command.subCommands
+// SubCommands are the visible sub-commands, in help order.
+func (h HelpData) SubCommands() []CommandHelp {
+	return lo.Map(h.subCommands(), func(command *Command, _ int) CommandHelp { return command.commandHelp() })
 }
 
-func (h helpContext) Arguments() []*Argument {
+// Arguments are the command's arguments, in order.
+func (h HelpData) Arguments() []ArgumentHelp {
+	return lo.Map(h.arguments(), func(argument *Argument, _ int) ArgumentHelp {
+		return ArgumentHelp{ArgumentSchema: argument.schema(h.choicesContext()), argument: argument}
+	})
+}
+
+// Flags are the command's own and inherited flags, including hidden ones, in help order.
+func (h HelpData) Flags() []FlagHelp {
+	return lo.Map(h.flags(), func(flag *Flag, _ int) FlagHelp { return h.command.flagHelp(flag) })
+}
+
+func (h HelpData) subCommands() []*Command {
+	return sortForHelp(h.command.visibleSubCommands(), h.command.findHelpSortOrder(), func(command *Command) string { return command.name })
+}
+
+func (h HelpData) arguments() []*Argument {
 	return h.command.arguments
 }
 
-func (h helpContext) Flags() []*Flag {
-	return h.command.flagsUpToRoot()
+func (h HelpData) flags() []*Flag {
+	return sortForHelp(h.command.flagsUpToRoot(), h.command.findHelpSortOrder(), func(flag *Flag) string { return flag.name })
+}
+
+// CommandGroups splits the sub-commands by SetCommandGroup, with ungrouped sub-commands first and groups in the order
+// they were first used.
+func (h HelpData) CommandGroups() []CommandGroup {
+	groups := groupForHelp(h.command.visibleSubCommands(), defaultCommandGroupTitle, func(command *Command) string { return command.group })
+	return lo.Map(groups, func(group lo.Tuple2[string, []*Command], _ int) CommandGroup {
+		commands := sortForHelp(group.B, h.command.findHelpSortOrder(), func(command *Command) string { return command.name })
+		return CommandGroup{Title: group.A, Commands: lo.Map(commands, func(command *Command, _ int) CommandHelp { return command.commandHelp() })}
+	})
+}
+
+// FlagGroups splits the command's own visible flags by SetFlagGroup, with ungrouped flags first and groups in the order
+// they were first used. Inherited flags are listed by GlobalFlags.
+func (h HelpData) FlagGroups() []FlagGroup {
+	flags := lo.Reject(h.command.flags, func(flag *Flag, _ int) bool { return flag.isHidden })
+	groups := groupForHelp(flags, defaultFlagGroupTitle, func(flag *Flag) string { return flag.group })
+	return lo.Map(groups, func(group lo.Tuple2[string, []*Flag], _ int) FlagGroup {
+		flags := sortForHelp(group.B, h.command.findHelpSortOrder(), func(flag *Flag) string { return flag.name })
+		return FlagGroup{Title: group.A, Flags: lo.Map(flags, func(flag *Flag, _ int) FlagHelp { return h.command.flagHelp(flag) })}
+	})
+}
+
+func (h HelpData) SubCommandsTable() (string, error) {
+	return h.CommandGroupTable(CommandGroup{Commands: h.SubCommands()})
 }
 
-func (h helpContext) SubCommandsTable() (string, error) {
-	return tableToString(lo.Map(h.SubCommands(), func(command *Command, _ int) []string {
+func (h HelpData) CommandGroupTable(group CommandGroup) (string, error) {
+	return h.table(lo.Map(group.Commands, func(command CommandHelp, _ int) []string {
 		return []string{
 			"",
-			fmt.Sprintf("%s: %s", command.name, command.description),
+			fmt.Sprintf("%s: %s", command.command.helpName(), command.Description),
 		}
-	}))
+	}), 1)
 }
 
-func (h helpContext) ArgumentList() string {
-	return strings.Join(lo.Map(h.Arguments(), func(argument *Argument, _ int) string { return argument.inBrackets() }), " ")
+func (h HelpData) ArgumentList() string {
+	return strings.Join(lo.Map(h.arguments(), func(argument *Argument, _ int) string {
+		return h.theme.Placeholder.Render(argument.inBrackets())
+	}), " ")
 }
 
-func (h helpContext) ArgumentTable() (string, error) {
-	return tableToString(lo.Map(h.Arguments(), func(argument *Argument, _ int) []string {
-		valueInfo := fmt.Sprintf("(type: %T)", argument.parser.Type())
-		if argument.isOptional() {
-			valueInfo = fmt.Sprintf("(type: %T, default: %q)", argument.parser.Type(), fmt.Sprint(argument.defaultValue))
-		}
-
+func (h HelpData) ArgumentTable() (string, error) {
+	return h.table(lo.Map(h.arguments(), func(argument *Argument, _ int) []string {
 		return []string{
 			"",
-			argument.inBrackets(),
+			h.theme.Placeholder.Render(argument.inBrackets()),
 			argument.description,
-			valueInfo,
+			fmt.Sprintf("(%s)", strings.Join(argument.helpValueInfo(h.choicesContext(), h.theme), ", ")),
 		}
-	}))
+	}), 2)
 }
 
-func (h helpContext) FlagTable() (string, error) {
-	return tableToString(lo.FilterMap(h.Flags(), func(flag *Flag, _ int) ([]string, bool) {
-		if flag.isHidden {
-			return nil, false
-		}
+func (h HelpData) FlagTable() (string, error) {
+	return h.FlagGroupTable(FlagGroup{Flags: h.Flags()})
+}
 
-		longs := lo.Map(append([]string{flag.name}, flag.aliases...), func(long string, _ int) string { return fmt.Sprintf("--%s", long) })
+func (h HelpData) FlagGroupTable(group FlagGroup) (string, error) {
+	return h.table(lo.FilterMap(group.Flags, func(flag FlagHelp, _ int) ([]string, bool) {
+		return h.flagRow(flag.flag, flag.flag.helpValueInfo(h.choicesContext(), h.theme)), !flag.Hidden
+	}), 3)
+}
 
-		shorts := ""
-		if len(flag.shorts) > 0 {
-			shorts = fmt.Sprintf("-%s", string(flag.shorts))
-		}
+// GlobalFlags are the visible flags the command inherits from its ancestors.
+func (h HelpData) GlobalFlags() []FlagHelp {
+	flags := lo.Filter(h.command.flagsUpToRoot(), func(flag *Flag, _ int) bool {
+		return !flag.isHidden && !lo.Contains(h.command.flags, flag)
+	})
 
-		helpValues := []string{fmt.Sprintf("%q", fmt.Sprint(flag.defaultValue))}
-		if flag.defaultEnvName != "" {
-			helpValues = append([]string{fmt.Sprintf("$%s", flag.defaultEnvName)}, helpValues...)
+	flags = sortForHelp(flags, h.command.findHelpSortOrder(), func(flag *Flag) string { return flag.name })
+	return lo.Map(flags, func(flag *Flag, _ int) FlagHelp { return h.command.flagHelp(flag) })
+}
+
+// GlobalFlagTable lists the global flags, noting the ancestor which defined each one.
+func (h HelpData) GlobalFlagTable() (string, error) {
+	return h.table(lo.Map(h.GlobalFlags(), func(flag FlagHelp, _ int) []string {
+		return h.flagRow(flag.flag, append(flag.flag.helpValueInfo(h.choicesContext(), h.theme), fmt.Sprintf("defined by: %s", flag.DefinedBy)))
+	}), 3)
+}
+
+func (h HelpData) flagRow(flag *Flag, valueInfo []string) []string {
+	return []string{
+		"",
+		h.theme.Flag.Render(strings.Join(flag.helpLongs(), " ")),
+		h.theme.Flag.Render(flag.helpShorts()),
+		flag.description,
+		fmt.Sprintf("(%s)", strings.Join(valueInfo, ", ")),
+	}
+}
+
+// Schema describes the command in a structured form, for templates which lay out flags and arguments themselves.
+func (h HelpData) Schema() CommandSchema {
+	return h.command.commandSchema(context.Background())
+}
+
+// FlagConstraints are the constraints between the command's flags.
+func (h HelpData) FlagConstraints() []FlagConstraintHelp {
+	return lo.Map(h.command.flagConstraints, func(constraint flagConstraint, _ int) FlagConstraintHelp { return constraint.help() })
+}
+
+func (h HelpData) FlagConstraintTable() (string, error) {
+	return h.table(lo.Map(h.FlagConstraints(), func(constraint FlagConstraintHelp, _ int) []string {
+		return []string{"", dashedFlagNames(constraint.Flags), constraint.Description}
+	}), 2)
+}
+
+func (c *Command) commandHelp() CommandHelp {
+	return CommandHelp{
+		Name:        c.name,
+		Aliases:     c.aliases,
+		Description: c.description,
+		Group:       c.group,
+		Deprecated:  c.deprecation,
+		ReplacedBy:  c.replacedBy,
+		command:     c,
+	}
+}
+
+// flagHelp describes a flag as seen from the command, noting the ancestor which defined it if it is inherited.
+func (c *Command) flagHelp(flag *Flag) FlagHelp {
+	schema := flag.schema(c.onContext(context.Background()))
+	if owner, found := c.findFlagOwner(flag); found && owner != c {
+		schema.DefinedBy = owner.qualifiedName()
+	}
+
+	return FlagHelp{FlagSchema: schema, flag: flag}
+}
+
+func (c *Command) helpName() string {
+	if len(c.aliases) == 0 {
+		return c.name
+	}
+
+	return fmt.Sprintf("%s (%s)", c.name, strings.Join(c.aliases, ", "))
+}
+
+// choicesContext is the context ChoicesFuncs are called with to list choices in help.
+func (h HelpData) choicesContext() context.Context {
+	return h.command.onContext(context.Background())
+}
+
+func (a *Argument) helpValueInfo(ctx context.Context, theme Theme) []string {
+	valueInfo := []string{fmt.Sprintf("type: %T", a.parser.Type())}
+	if a.defaultValue != nil {
+		valueInfo = append(valueInfo, fmt.Sprintf("default: %s", theme.Default.Render(fmt.Sprintf("%q", fmt.Sprint(a.defaultValue)))))
+	}
+
+	if a.isVariadic && a.minCount > 1 {
+		valueInfo = append(valueInfo, fmt.Sprintf("min: %d", a.minCount))
+	}
+
+	if a.isVariadic && a.maxCount > 0 {
+		valueInfo = append(valueInfo, fmt.Sprintf("max: %d", a.maxCount))
+	}
+
+	if choices := a.allowedChoices(ctx); len(choices) > 0 {
+		valueInfo = append(valueInfo, fmt.Sprintf("choices: %s", strings.Join(choices, "|")))
+	}
+
+	if a.deprecation != "" {
+		valueInfo = append(valueInfo, fmt.Sprintf("deprecated: %s", a.deprecation))
+	}
+
+	return valueInfo
+}
+
+func (f *Flag) helpLongs() []string {
+	return lo.Map(append([]string{f.name}, f.aliases...), func(long string, _ int) string {
+		if f.isNegatable {
+			return fmt.Sprintf("--[%s]%s", negationPrefix, long)
 		}
 
-		return []string{
-			"",
-			strings.Join(longs, " "),
-			shorts,
-			flag.description,
-			fmt.Sprintf("(type: %T, default: %s)", flag.parser.Type(), strings.Join(helpValues, ", ")),
-		}, true
-	}))
+		return fmt.Sprintf("--%s", long)
+	})
+}
+
+func (f *Flag) helpShorts() string {
+	if len(f.shorts) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("-%s", string(f.shorts))
+}
+
+func (f *Flag) helpValueInfo(ctx context.Context, theme Theme) []string {
+	var helpValues []string
+	if f.defaultEnvName != "" {
+		helpValues = append(helpValues, fmt.Sprintf("$%s", f.defaultEnvName))
+	}
+
+	if !f.isRequired {
+		helpValues = append(helpValues, fmt.Sprintf("%q", fmt.Sprint(f.defaultValue)))
+	}
+
+	valueInfo := []string{fmt.Sprintf("type: %T", f.parser.Type())}
+	if len(helpValues) > 0 {
+		valueInfo = append(valueInfo, fmt.Sprintf("default: %s", theme.Default.Render(strings.Join(helpValues, ", "))))
+	}
+
+	if f.isRequired {
+		valueInfo = append(valueInfo, "required")
+	}
+
+	if f.isRepeatable() {
+		valueInfo = append(valueInfo, "repeatable")
+	}
+
+	if f.isCounter {
+		valueInfo = append(valueInfo, "counter")
+	}
+
+	if choices := f.allowedChoices(ctx); len(choices) > 0 {
+		valueInfo = append(valueInfo, fmt.Sprintf("choices: %s", strings.Join(choices, "|")))
+	}
+
+	return valueInfo
 }
 
-func tableToString(rows [][]string) (string, error) {
+// table renders rows as an aligned table. If the table is wider than the help width, the cells from wrapColumn on are
+// joined and wrapped with a hanging indent.
+func (h HelpData) table(rows [][]string, wrapColumn int) (string, error) {
 	buffer := new(bytes.Buffer)
-	if err := writeTable(buffer, rows); err != nil {
+	if err := writeTable(buffer, rows, h.width, wrapColumn); err != nil {
 		return "", errors.Wrap(err, "writing table to string")
 	}
 
 	return buffer.String(), nil
 }
 
-func writeTable(w io.Writer, rows [][]string) error {
-	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+// writeTable writes rows with their columns aligned, measuring cells by their display width so styled and wide cells
+// line up.
+func writeTable(w io.Writer, rows [][]string, width, wrapColumn int) error {
+	widths := columnWidths(rows)
+	if width <= 0 || wrapColumn >= len(widths) || tableWidth(widths) <= width {
+		return writeRows(w, rows, widths)
+	}
+
+	rows = lo.Map(rows, func(row []string, _ int) []string {
+		if len(row) <= wrapColumn {
+			return row
+		}
+
+		wrapped := strings.Join(lo.Compact(row[wrapColumn:]), " ")
+		return append(append([]string{}, row[:wrapColumn]...), wrapped)
+	})
 
+	widths = columnWidths(rows)
+	indent := tableWidth(widths[:wrapColumn]) + tablePadding
+	limit := max(width-indent, minWrapColumnSize)
 	for _, row := range rows {
-		if _, err := fmt.Fprintln(table, strings.Join(row, "\t")); err != nil {
-			return errors.Wrap(err, "writing table row")
+		if len(row) <= wrapColumn {
+			if err := writeRows(w, [][]string{row}, widths); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		lines := wrapText(row[wrapColumn], limit)
+		first := append(append([]string{}, row[:wrapColumn]...), lines[0])
+		if err := writeRows(w, [][]string{first}, widths); err != nil {
+			return err
+		}
+
+		for _, line := range lines[1:] {
+			if _, err := fmt.Fprintln(w, strings.Repeat(" ", indent)+line); err != nil {
+				return errors.Wrap(err, "writing table row")
+			}
 		}
 	}
 
-	if err := table.Flush(); err != nil {
-		return errors.Wrap(err, "flushing table")
+	return nil
+}
+
+func writeRows(w io.Writer, rows [][]string, widths []int) error {
+	for _, row := range rows {
+		line := new(strings.Builder)
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+tablePadding))
+			}
+		}
+
+		if _, err := fmt.Fprintln(w, line.String()); err != nil {
+			return errors.Wrap(err, "writing table row")
+		}
 	}
 
 	return nil
 }
+
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+
+	return widths
+}
+
+func tableWidth(widths []int) int {
+	return lo.Sum(widths) + max(len(widths)-1, 0)*tablePadding
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+const (
+	defaultCommandGroupTitle = "Sub-commands"
+	defaultFlagGroupTitle    = "Flags"
+)
+
+// HelpSortOrder is the order sub-commands and flags are listed in help.
+type HelpSortOrder int
+
+const (
+	// SortDeclared lists sub-commands and flags in the order they were added.
+	SortDeclared HelpSortOrder = iota
+
+	// SortAlphabetical lists sub-commands and flags by name.
+	SortAlphabetical
+)
+
+// CommandGroup is a titled section of sub-commands in help.
+type CommandGroup struct {
+	Title    string
+	Commands []CommandHelp
+}
+
+// FlagGroup is a titled section of flags in help.
+type FlagGroup struct {
+	Title string
+	Flags []FlagHelp
+}
+
+func (c *Command) findHelpSortOrder() HelpSortOrder {
+	for current := c; current != nil; current = current.parent {
+		if current.helpSortOrder != nil {
+			return *current.helpSortOrder
+		}
+	}
+
+	return SortDeclared
+}
+
+func sortForHelp[T any](items []T, order HelpSortOrder, name func(T) string) []T {
+	sorted := append([]T{}, items...)
+	if order == SortAlphabetical {
+		sort.SliceStable(sorted, func(i, j int) bool { return name(sorted[i]) < name(sorted[j]) })
+	}
+
+	return sorted
+}
+
+// groupForHelp splits items into groups in order of first appearance, with ungrouped items first under defaultTitle.
+func groupForHelp[T any](items []T, defaultTitle string, group func(T) string) []lo.Tuple2[string, []T] {
+	titles := []string{defaultTitle}
+	itemsByTitle := make(map[string][]T)
+	for _, item := range items {
+		title := lo.CoalesceOrEmpty(group(item), defaultTitle)
+		if !lo.Contains(titles, title) {
+			titles = append(titles, title)
+		}
+
+		itemsByTitle[title] = append(itemsByTitle[title], item)
+	}
+
+	return lo.FilterMap(titles, func(title string, _ int) (lo.Tuple2[string, []T], bool) {
+		return lo.T2(title, itemsByTitle[title]), len(itemsByTitle[title]) > 0
+	})
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/broothie/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_renderHelp_groups(t *testing.T) {
+	type TestCase struct {
+		commandOptions option.Options[*Command]
+		expected       string
+	}
+
+	groupedOptions := option.NewOptions(
+		AddSubCmd("run", "Run a container", SetCommandGroup("Container")),
+		AddSubCmd("login", "Log in"),
+		AddSubCmd("exec", "Exec into a container", SetCommandGroup("Container")),
+		AddSubCmd("context", "Manage contexts", SetCommandGroup("Management")),
+		AddFlag("port", "Port to listen on", SetFlagGroup("Networking"), SetFlagDefault(80)),
+		AddFlag("verbose", "Verbose output", SetFlagDefault(false)),
+		AddFlag("host", "Host to bind", SetFlagGroup("Networking")),
+		AddFlag("debug", "Debug output", SetFlagDefault(false), SetFlagIsHidden(true)),
+	)
+
+	testCases := map[string]TestCase{
+		"declared order": {
+			commandOptions: groupedOptions,
+			expected: heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags] [sub-command]
+
+				Sub-commands:
+				  login: Log in
+
+				Container:
+				  run: Run a container
+				  exec: Exec into a container
+
+				Management:
+				  context: Manage contexts
+
+				Flags:
+				  --verbose    Verbose output  (type: bool, default: "false")
+
+				Networking:
+				  --port    Port to listen on  (type: int, default: "80")
+				  --host    Host to bind       (type: string, default: "")
+
+			`),
+		},
+		"alphabetical order": {
+			commandOptions: append(groupedOptions, SetHelpSortOrder(SortAlphabetical)),
+			expected: heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags] [sub-command]
+
+				Sub-commands:
+				  login: Log in
+
+				Container:
+				  exec: Exec into a container
+				  run: Run a container
+
+				Management:
+				  context: Manage contexts
+
+				Flags:
+				  --verbose    Verbose output  (type: bool, default: "false")
+
+				Networking:
+				  --host    Host to bind       (type: string, default: "")
+				  --port    Port to listen on  (type: int, default: "80")
+
+			`),
+		},
+		"group named like the default": {
+			commandOptions: option.NewOptions(
+				AddFlag("verbose", "Verbose output", SetFlagDefault(false)),
+				AddFlag("quiet", "Quiet output", SetFlagDefault(false), SetFlagGroup("Flags")),
+			),
+			expected: heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags]
+
+				Flags:
+				  --verbose    Verbose output  (type: bool, default: "false")
+				  --quiet      Quiet output    (type: bool, default: "false")
+
+			`),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			command, err := NewCommand("test", "test command", testCase.commandOptions...)
+			require.NoError(t, err)
+
+			buffer := new(bytes.Buffer)
+			require.NoError(t, command.renderHelp(buffer))
+			assert.Equal(t, testCase.expected, buffer.String())
+		})
+	}
+}
+
+func TestCommand_findHelpSortOrder(t *testing.T) {
+	command, err := NewCommand("test", "test command",
+		SetHelpSortOrder(SortAlphabetical),
+		AddSubCmd("inherits", "inherits sort order"),
+		AddSubCmd("overrides", "overrides sort order", SetHelpSortOrder(SortDeclared)),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, SortAlphabetical, command.findHelpSortOrder())
+	assert.Equal(t, SortAlphabetical, command.subCommands[0].findHelpSortOrder())
+	assert.Equal(t, SortDeclared, command.subCommands[1].findHelpSortOrder())
+}
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -126,10 +128,366 @@ func TestCommand_renderHelp(t *testing.T) {
 
 				Flags:
 				  --some-flag    some flag  (type: string, default: "")
-				  --inherited    inherited  (type: string, default: "")
 
+				Global flags:
+				  --inherited    inherited  (type: string, default: "", defined by: test)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("nested global flags", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("verbose", "verbose output", AddFlagShort('v'), SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddFlag("uninherited", "not inherited"),
+			AddSubCmd("remote", "manage remotes",
+				AddFlag("remote-name", "remote to use", SetFlagDefault("origin"), SetFlagIsInherited(true)),
+				AddSubCmd("add", "add a remote",
+					AddFlag("fetch", "fetch after adding", SetFlagDefault(false)),
+				),
+			),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.subCommands[0].subCommands[0].renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test remote add [flags]
+
+				Flags:
+				  --fetch    fetch after adding  (type: bool, default: "false")
+
+				Global flags:
+				  --remote-name      remote to use   (type: string, default: "origin", defined by: test remote)
+				  --verbose      -v  verbose output  (type: bool, default: "false", defined by: test)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("repeatable flags", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("include", "include path", AddFlagShort('I'), SetFlagDefault([]string{})),
+			AddFlag("label", "label", SetFlagDefault(map[string]string{})),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags]
+
+				Flags:
+				  --include  -I  include path  (type: []string, default: "[]", repeatable)
+				  --label        label         (type: map[string]string, default: "map[]", repeatable)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("variadic argument", func(t *testing.T) {
+		command, err := NewCommand("rm", "remove files",
+			AddArg("file", "files to remove", SetArgVariadicCount(1, 3)),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				rm: remove files
+
+				Usage:
+				  rm <file>...
+
+				Arguments:
+				  <file>...  files to remove  (type: []string, max: 3)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("negatable flag", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("color", "colorize output", AddFlagAlias("colour"), SetFlagDefault(true), SetFlagNegatable(true)),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags]
+
+				Flags:
+				  --[no-]color --[no-]colour    colorize output  (type: bool, default: "true")
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("counter flag", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("verbose", "verbosity", AddFlagShort('v'), SetFlagCounter()),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags]
+
+				Flags:
+				  --verbose  -v  verbosity  (type: int, default: "0", counter)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("choices", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("format", "output format",
+				SetFlagDefault("table"),
+				SetFlagChoices("json", "yaml", "table"),
+				SetFlagChoicesFunc(func(context.Context) []string { return []string{"csv"} }),
+			),
+			AddArg("level", "log level", SetArgChoices("debug", "info")),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags] <level>
+
+				Arguments:
+				  <level>  log level  (type: string, choices: debug|info)
+
+				Flags:
+				  --format    output format  (type: string, default: "table", choices: json|yaml|table|csv)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("required flag", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("token", "API token", SetFlagRequired(true)),
+			AddFlag("region", "region", SetFlagRequired(true), SetFlagDefaultEnv("REGION")),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags]
+
+				Flags:
+				  --token     API token  (type: string, required)
+				  --region    region     (type: string, default: $REGION, required)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("flag constraints", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddFlag("json", "json output", SetFlagDefault(false)),
+			AddFlag("table", "table output", SetFlagDefault(false)),
+			AddFlag("auth", "auth method", SetFlagDefault("none")),
+			AddFlag("password", "password"),
+			AddMutuallyExclusiveFlags("json", "table"),
+			AddFlagRequiredIf("password", "auth", "basic"),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [flags]
+
+				Flags:
+				  --json        json output   (type: bool, default: "false")
+				  --table       table output  (type: bool, default: "false")
+				  --auth        auth method   (type: string, default: "none")
+				  --password    password      (type: string, default: "")
+
+				Flag constraints:
+				  --json, --table  mutually exclusive
+				  --password       required when --auth=basic
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("sub-command aliases", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			AddSubCmd("status", "Show status", AddAlias("st"), AddAlias("stat")),
+			AddSubCmd("commit", "Record changes"),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				test: test command
+
+				Usage:
+				  test [sub-command]
+
+				Sub-commands:
+				  status (st, stat): Show status
+				  commit: Record changes
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			SetHelpTemplate(heredoc.Doc(`
+				== {{ .Name }} ==
+				{{ .Description }}
+				{{ range .Schema.Flags }}* --{{ .Name }}: {{ .Description }}
+				{{ end -}}
+			`)),
+			AddFlag("verbose", "be verbose", SetFlagDefault(false)),
+			AddSubCmd("sub", "sub-command", AddFlag("force", "force it", SetFlagDefault(false))),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.subCommands[0].renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				== sub ==
+				sub-command
+				* --force: force it
 			`),
 			buffer.String(),
 		)
 	})
+
+	t.Run("custom template views", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			SetHelpTemplate(heredoc.Doc(`
+				{{ range .SubCommands }}{{ .Name }} ({{ .Group }}): {{ .Description }}
+				{{ end -}}
+				{{ range .Flags }}--{{ .Name }} {{ .Type }}{{ if .DefinedBy }} from {{ .DefinedBy }}{{ end }}
+				{{ end -}}
+				{{ range .FlagConstraints }}{{ .Flags }} {{ .Description }}
+				{{ end -}}
+			`)),
+			AddFlag("verbose", "be verbose", SetFlagDefault(false), SetFlagIsInherited(true)),
+			AddFlag("json", "json output", SetFlagDefault(false)),
+			AddFlag("table", "table output", SetFlagDefault(false)),
+			AddMutuallyExclusiveFlags("json", "table"),
+			AddSubCmd("sub", "sub-command", SetCommandGroup("Main"), AddArg("target", "target")),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.renderHelp(buffer))
+		assert.Equal(t,
+			heredoc.Doc(`
+				sub (Main): sub-command
+				--verbose bool
+				--json bool
+				--table bool
+				[json table] mutually exclusive
+			`),
+			buffer.String(),
+		)
+
+		buffer.Reset()
+		assert.NoError(t, command.subCommands[0].renderHelp(buffer))
+		assert.Equal(t, "--verbose bool from test\n", buffer.String())
+	})
+
+	t.Run("invalid custom template", func(t *testing.T) {
+		_, err := NewCommand("test", "test command", SetHelpTemplate("{{ .Name "))
+		assert.ErrorContains(t, err, "parsing help template")
+	})
+
+	t.Run("custom renderer", func(t *testing.T) {
+		command, err := NewCommand("test", "test command",
+			SetHelpRenderer(func(w io.Writer, data HelpData) error {
+				_, err := fmt.Fprintf(w, "%s: %s\n", data.QualifiedName(), data.Description())
+				return err
+			}),
+			AddSubCmd("sub", "sub-command",
+				AddSubCmd("nested", "nested sub-command"),
+			),
+			AddSubCmd("other", "other sub-command", SetHelpTemplate("other")),
+		)
+
+		assert.NoError(t, err)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, command.subCommands[0].subCommands[0].renderHelp(buffer))
+		assert.Equal(t, "test sub nested: nested sub-command\n", buffer.String())
+
+		buffer.Reset()
+		assert.NoError(t, command.subCommands[1].renderHelp(buffer))
+		assert.Equal(t, "other", buffer.String())
+	})
 }
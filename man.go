@@ -0,0 +1,177 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+const manSection = "1"
+
+//go:embed man.tmpl
+var rawManTemplate string
+
+var manTemplate = template.Must(template.New("man").
+	Funcs(template.FuncMap{
+		"roff":      roffEscape,
+		"roffQuote": roffQuote,
+		"upper":     strings.ToUpper,
+		"join":      strings.Join,
+	}).
+	Parse(rawManTemplate),
+)
+
+// WriteManPages writes a roff man page for each command in the command tree to dir, named after the command's
+// qualified name, e.g. "git-commit.1".
+func (c *Command) WriteManPages(dir string) error {
+	if err := c.root().validateTree(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateExamples(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrapf(err, "creating man page directory %q", dir)
+	}
+
+	var errs []error
+	c.root().walk(func(command *Command) {
+		if !command.isHiddenUpToRoot() {
+			errs = append(errs, command.writeManPageFile(dir))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+func (c *Command) writeManPageFile(dir string) error {
+	path := filepath.Join(dir, c.manPageFileName())
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating man page %q", path)
+	}
+	defer file.Close()
+
+	if err := c.renderManPage(file); err != nil {
+		return errors.Wrapf(err, "writing man page %q", path)
+	}
+
+	return file.Close()
+}
+
+func (c *Command) renderManPage(w io.Writer) error {
+	if err := manTemplate.Execute(w, manContext{HelpData: c.helpData()}); err != nil {
+		return errors.Wrap(err, "man template")
+	}
+
+	return nil
+}
+
+func (c *Command) manPageName() string {
+	return strings.ReplaceAll(c.qualifiedName(), " ", "-")
+}
+
+func (c *Command) manPageFileName() string {
+	return fmt.Sprintf("%s.%s", c.manPageName(), manSection)
+}
+
+type manContext struct {
+	HelpData
+}
+
+type manEntry struct {
+	Name        string
+	Description string
+}
+
+func (m manContext) PageName() string {
+	return m.command.manPageName()
+}
+
+func (m manContext) Section() string {
+	return manSection
+}
+
+func (m manContext) Source() string {
+	if version := m.Version(); version != "" {
+		return fmt.Sprintf("%s %s", m.RootName(), version)
+	}
+
+	return m.RootName()
+}
+
+func (m manContext) ManSubCommands() []manEntry {
+	return lo.Map(m.subCommands(), func(command *Command, _ int) manEntry {
+		return manEntry{Name: command.helpName(), Description: command.description}
+	})
+}
+
+func (m manContext) ManArguments() []manEntry {
+	return lo.Map(m.arguments(), func(argument *Argument, _ int) manEntry {
+		return manEntry{
+			Name:        argument.inBrackets(),
+			Description: fmt.Sprintf("%s (%s)", argument.description, strings.Join(argument.helpValueInfo(m.choicesContext(), m.theme), ", ")),
+		}
+	})
+}
+
+func (m manContext) ManFlags() []manEntry {
+	return lo.FilterMap(m.flags(), func(flag *Flag, _ int) (manEntry, bool) {
+		names := flag.helpLongs()
+		if shorts := flag.helpShorts(); shorts != "" {
+			names = append(names, shorts)
+		}
+
+		return manEntry{
+			Name:        strings.Join(names, ", "),
+			Description: fmt.Sprintf("%s (%s)", flag.description, strings.Join(flag.helpValueInfo(m.choicesContext(), m.theme), ", ")),
+		}, !flag.isHidden
+	})
+}
+
+func (m manContext) EnvFlags() []manEntry {
+	return lo.FilterMap(m.flags(), func(flag *Flag, _ int) (manEntry, bool) {
+		return manEntry{
+			Name:        flag.defaultEnvName,
+			Description: fmt.Sprintf("Default value of --%s.", flag.name),
+		}, !flag.isHidden && flag.defaultEnvName != ""
+	})
+}
+
+func (m manContext) SeeAlso() []string {
+	var commands []*Command
+	if m.command.hasParent() {
+		commands = append(commands, m.command.parent)
+	}
+
+	commands = append(commands, m.subCommands()...)
+	return lo.Map(commands, func(command *Command, _ int) string {
+		return fmt.Sprintf("%s(%s)", command.manPageName(), manSection)
+	})
+}
+
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func roffQuote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(roffEscape(s), `"`, `\(dq`))
+}
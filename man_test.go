@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_renderManPage(t *testing.T) {
+	command, err := NewCommand("server", "An http server.",
+		SetVersion("v0.1.0"),
+		AddHelpFlag(AddFlagShort('h'), SetFlagIsInherited(true)),
+		AddFlag("port", "Port to run server on.", AddFlagShort('p'), SetFlagDefault(3000), SetFlagDefaultEnv("PORT")),
+		AddFlag("debug", "Enable debugging.", SetFlagDefault(false), SetFlagIsHidden(true), SetFlagDefaultEnv("DEBUG")),
+		AddSubCmd("proxy", "Proxy requests to another server.",
+			AddAlias("p"),
+			AddArg("target", "Target server to proxy requests to.", SetArgParser(URLParser)),
+			AddArg("timeout", "Request timeout.", SetArgDefault("-1s")),
+		),
+	)
+
+	require.NoError(t, err)
+
+	t.Run("root", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.renderManPage(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				.TH "SERVER" "1" "" "server v0.1.0" "server manual"
+				.SH NAME
+				server \- An http server.
+				.SH SYNOPSIS
+				.B server
+				[flags]
+				[sub-command]
+				.SH DESCRIPTION
+				An http server.
+				.SH COMMANDS
+				.TP
+				.B proxy (p)
+				Proxy requests to another server.
+				.SH OPTIONS
+				.TP
+				.B \-\-help, \-h
+				Print help. (type: bool, default: "false")
+				.TP
+				.B \-\-port, \-p
+				Port to run server on. (type: int, default: $PORT, "3000")
+				.SH ENVIRONMENT
+				.TP
+				.B PORT
+				Default value of \-\-port.
+				.SH SEE ALSO
+				server\-proxy(1)
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("sub-command", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.subCommands[0].renderManPage(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				.TH "SERVER\-PROXY" "1" "" "server v0.1.0" "server manual"
+				.SH NAME
+				server\-proxy \- Proxy requests to another server.
+				.SH SYNOPSIS
+				.B server proxy
+				[flags]
+				<target> [<timeout>]
+				.SH DESCRIPTION
+				Proxy requests to another server.
+				.SH ARGUMENTS
+				.TP
+				.B <target>
+				Target server to proxy requests to. (type: *url.URL)
+				.TP
+				.B [<timeout>]
+				Request timeout. (type: string, default: "\-1s")
+				.SH OPTIONS
+				.TP
+				.B \-\-help, \-h
+				Print help. (type: bool, default: "false")
+				.SH SEE ALSO
+				server(1)
+			`),
+			buffer.String(),
+		)
+	})
+}
+
+func TestCommand_WriteManPages(t *testing.T) {
+	command, err := NewCommand("server", "An http server.", AddSubCmd("proxy", "Proxy requests to another server."))
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "man1")
+	require.NoError(t, command.subCommands[0].WriteManPages(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server-proxy.1", "server.1"}, []string{entries[0].Name(), entries[1].Name()})
+}
+
+func Test_roffEscape(t *testing.T) {
+	assert.Equal(t, `\-\-flag`, roffEscape("--flag"))
+	assert.Equal(t, `C:\epath`, roffEscape(`C:\path`))
+	assert.Equal(t, "\\&.starts with a dot\n\\&'and a quote", roffEscape(".starts with a dot\n'and a quote"))
+	assert.Equal(t, `"say \(dqhi\(dq"`, roffQuote(`say "hi"`))
+}
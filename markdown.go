@@ -0,0 +1,174 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+//go:embed markdown.tmpl
+var rawMarkdownTemplate string
+
+var markdownTemplate = template.Must(template.New("markdown").Parse(rawMarkdownTemplate))
+
+// WriteMarkdownDocs writes a Markdown reference page for each command in the command tree to dir, named after the
+// command's qualified name, e.g. "git-commit.md". Pages link to their parent and sub-commands.
+func (c *Command) WriteMarkdownDocs(dir string) error {
+	if err := c.root().validateTree(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateExamples(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrapf(err, "creating markdown directory %q", dir)
+	}
+
+	var errs []error
+	c.root().walk(func(command *Command) {
+		if !command.isHiddenUpToRoot() {
+			errs = append(errs, command.writeMarkdownFile(dir))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+func (c *Command) writeMarkdownFile(dir string) error {
+	path := filepath.Join(dir, c.markdownFileName())
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating markdown page %q", path)
+	}
+	defer file.Close()
+
+	if err := c.renderMarkdown(file); err != nil {
+		return errors.Wrapf(err, "writing markdown page %q", path)
+	}
+
+	return file.Close()
+}
+
+func (c *Command) renderMarkdown(w io.Writer) error {
+	if err := markdownTemplate.Execute(w, markdownContext{HelpData: c.helpData()}); err != nil {
+		return errors.Wrap(err, "markdown template")
+	}
+
+	return nil
+}
+
+func (c *Command) markdownFileName() string {
+	return fmt.Sprintf("%s.md", c.manPageName())
+}
+
+func (c *Command) markdownLink() string {
+	return fmt.Sprintf("[%s](%s)", c.qualifiedName(), c.markdownFileName())
+}
+
+type markdownContext struct {
+	HelpData
+}
+
+type markdownRow struct {
+	Name        string
+	Aliases     string
+	Short       string
+	Description string
+	Details     string
+	DefinedBy   string
+}
+
+func (m markdownContext) Parent() string {
+	if m.command.isRoot() {
+		return ""
+	}
+
+	return m.command.parent.markdownLink()
+}
+
+func (m markdownContext) Usage() string {
+	parts := []string{m.QualifiedName()}
+	if len(m.flags()) > 0 {
+		parts = append(parts, "[flags]")
+	}
+
+	if len(m.subCommands()) > 0 {
+		parts = append(parts, "[sub-command]")
+	}
+
+	if argumentList := m.ArgumentList(); argumentList != "" {
+		parts = append(parts, argumentList)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (m markdownContext) MarkdownSubCommands() []markdownRow {
+	return lo.Map(m.subCommands(), func(command *Command, _ int) markdownRow {
+		return markdownRow{
+			Name:        command.markdownLink(),
+			Aliases:     markdownCell(strings.Join(command.aliases, ", ")),
+			Description: markdownCell(command.description),
+		}
+	})
+}
+
+func (m markdownContext) MarkdownArguments() []markdownRow {
+	return lo.Map(m.arguments(), func(argument *Argument, _ int) markdownRow {
+		return markdownRow{
+			Name:        markdownCode(argument.inBrackets()),
+			Description: markdownCell(argument.description),
+			Details:     markdownCell(strings.Join(argument.helpValueInfo(m.choicesContext(), m.theme), ", ")),
+		}
+	})
+}
+
+func (m markdownContext) MarkdownFlags() []markdownRow {
+	return m.markdownFlagRows(func(flag *Flag) bool { return lo.Contains(m.command.flags, flag) })
+}
+
+func (m markdownContext) MarkdownGlobalFlags() []markdownRow {
+	return m.markdownFlagRows(func(flag *Flag) bool { return !lo.Contains(m.command.flags, flag) })
+}
+
+func (m markdownContext) markdownFlagRows(predicate func(*Flag) bool) []markdownRow {
+	return lo.FilterMap(m.flags(), func(flag *Flag, _ int) (markdownRow, bool) {
+		if flag.isHidden || !predicate(flag) {
+			return markdownRow{}, false
+		}
+
+		row := markdownRow{
+			Name:        markdownCode(strings.Join(flag.helpLongs(), " ")),
+			Short:       markdownCode(flag.helpShorts()),
+			Description: markdownCell(flag.description),
+			Details:     markdownCell(strings.Join(flag.helpValueInfo(m.choicesContext(), m.theme), ", ")),
+		}
+
+		if owner, found := m.command.findFlagOwner(flag); found {
+			row.DefinedBy = owner.markdownLink()
+		}
+
+		return row, true
+	})
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("`%s`", markdownCell(s))
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_renderMarkdown(t *testing.T) {
+	command, err := NewCommand("git", "the stupid content tracker",
+		SetVersion("2.37.0"),
+		AddHelpFlag(AddFlagShort('h'), SetFlagIsInherited(true)),
+		AddFlag("debug", "Enable debugging", SetFlagDefault(false), SetFlagIsHidden(true), SetFlagIsInherited(true)),
+		AddSubCmd("commit", "Record changes to the repository",
+			AddAlias("ci"),
+			AddFlag("message", "commit message", AddFlagShort('m')),
+			AddFlag("cleanup", "cleanup mode", SetFlagDefault("default"), SetFlagChoices("default", "strip")),
+		),
+		AddSubCmd("add", "Add file contents to the index",
+			AddArg("paths", "Files to add", SetArgVariadic()),
+		),
+	)
+
+	require.NoError(t, err)
+
+	t.Run("root", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.renderMarkdown(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				# git
+
+				the stupid content tracker
+
+				Version: `+"`2.37.0`"+`
+
+				## Usage
+
+				`+"```"+`
+				git [flags] [sub-command]
+				`+"```"+`
+
+				## Sub-commands
+
+				| Name | Aliases | Description |
+				| --- | --- | --- |
+				| [git commit](git-commit.md) | ci | Record changes to the repository |
+				| [git add](git-add.md) |  | Add file contents to the index |
+
+				## Flags
+
+				| Name | Short | Description | Details |
+				| --- | --- | --- | --- |
+				| `+"`--help`"+` | `+"`-h`"+` | Print help. | type: bool, default: "false" |
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("sub-command", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.subCommands[0].renderMarkdown(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				# git commit
+
+				Record changes to the repository
+
+				Version: `+"`2.37.0`"+`
+
+				Parent command: [git](git.md)
+
+				## Usage
+
+				`+"```"+`
+				git commit [flags]
+				`+"```"+`
+
+				## Flags
+
+				| Name | Short | Description | Details |
+				| --- | --- | --- | --- |
+				| `+"`--message`"+` | `+"`-m`"+` | commit message | type: string, default: "" |
+				| `+"`--cleanup`"+` |  | cleanup mode | type: string, default: "default", choices: default\|strip |
+
+				## Global flags
+
+				| Name | Short | Description | Details | Defined by |
+				| --- | --- | --- | --- | --- |
+				| `+"`--help`"+` | `+"`-h`"+` | Print help. | type: bool, default: "false" | [git](git.md) |
+			`),
+			buffer.String(),
+		)
+	})
+}
+
+func TestCommand_WriteMarkdownDocs(t *testing.T) {
+	command, err := NewCommand("git", "the stupid content tracker",
+		AddSubCmd("commit", "Record changes to the repository"),
+		AddSubCmd("add", "Add file contents to the index"),
+	)
+
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, command.WriteMarkdownDocs(dir))
+
+	for _, name := range []string{"git.md", "git-commit.md", "git-add.md"} {
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.NotEmpty(t, contents)
+	}
+}
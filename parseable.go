@@ -11,8 +11,11 @@ import (
 var NotParseableError = errors.New("type not parseable")
 
 // Parseable is a type that can be parsed from a string.
+// Slice and map types make for repeatable flags, whose values are collected across occurrences and split on commas.
 type Parseable interface {
-	string | bool | int | float64 | time.Time | time.Duration | *url.URL
+	string | bool | int | float64 | time.Time | time.Duration | *url.URL |
+		[]string | []bool | []int | []float64 | []time.Time | []time.Duration | []*url.URL |
+		map[string]string
 }
 
 // StringParser parses a string into a string.
@@ -59,7 +62,11 @@ func URLParser(s string) (*url.URL, error) {
 
 func argParserFromParseable[T Parseable]() (argParser, error) {
 	var t T
-	switch any(t).(type) {
+	return argParserForValue(t)
+}
+
+func argParserForValue(value any) (argParser, error) {
+	switch value.(type) {
 	case string:
 		return NewArgParser(StringParser), nil
 
@@ -81,7 +88,31 @@ func argParserFromParseable[T Parseable]() (argParser, error) {
 	case *url.URL:
 		return NewArgParser(URLParser), nil
 
+	case []string:
+		return newSliceArgParser(StringParser), nil
+
+	case []bool:
+		return newSliceArgParser(BoolParser), nil
+
+	case []int:
+		return newSliceArgParser(IntParser), nil
+
+	case []float64:
+		return newSliceArgParser(Float64Parser), nil
+
+	case []time.Time:
+		return newSliceArgParser(TimeParser), nil
+
+	case []time.Duration:
+		return newSliceArgParser(DurationParser), nil
+
+	case []*url.URL:
+		return newSliceArgParser(URLParser), nil
+
+	case map[string]string:
+		return newMapArgParser(StringParser), nil
+
 	default:
-		return nil, errors.Wrapf(NotParseableError, "type %T", t)
+		return nil, errors.Wrapf(NotParseableError, "type %T", value)
 	}
 }
@@ -2,7 +2,11 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bobg/errors"
 	"github.com/samber/lo"
@@ -11,27 +15,35 @@ import (
 const (
 	flagPrefix     = "-"
 	longFlagPrefix = "--"
+	terminator     = "--"
 )
 
 var (
-	InvalidFlagError        = errors.New("invalid flag")
-	MissingFlagValueError   = errors.New("missing flag value")
-	TooManyArgumentsError   = errors.New("too many arguments")
-	FlagGroupWithEqualError = errors.New("short flags with equal signs cannot be grouped")
+	InvalidFlagError         = errors.New("invalid flag")
+	MissingFlagValueError    = errors.New("missing flag value")
+	TooManyArgumentsError    = errors.New("too many arguments")
+	FlagGroupWithEqualError  = errors.New("short flags with equal signs cannot be grouped")
+	AmbiguousSubCommandError = errors.New("ambiguous sub-command")
+	UnknownSubCommandError   = errors.New("unknown sub-command")
 )
 
 type parser struct {
-	command *Command
-	tokens  []string
+	command       *Command
+	tokens        []string
+	runSubCommand func(*Command, context.Context, []string) error
+	warnings      io.Writer
 
 	index         int
 	argumentIndex int
+	isTerminated  bool
 }
 
 func newParser(command *Command, tokens []string) *parser {
 	return &parser{
-		command: command,
-		tokens:  tokens,
+		command:       command,
+		tokens:        tokens,
+		runSubCommand: (*Command).Run,
+		warnings:      os.Stderr,
 	}
 }
 
@@ -40,6 +52,10 @@ func (c *Command) newParser(tokens []string) *parser {
 }
 
 func (p *parser) parse(ctx context.Context) (bool, error) {
+	p.command.restArgs = nil
+	p.command.resetFlags()
+	p.command.resetArguments()
+
 	for p.index < len(p.tokens) {
 		commandProcessed, err := p.parseArg(ctx)
 		if err != nil || commandProcessed {
@@ -53,39 +69,62 @@ func (p *parser) parse(ctx context.Context) (bool, error) {
 func (p *parser) parseArg(ctx context.Context) (bool, error) {
 	current, _ := p.current()
 
-	if strings.HasPrefix(current, flagPrefix) {
-		return false, p.processFlag()
-	} else if command, found := lo.Find(p.command.subCommands, func(subCommand *Command) bool { return subCommand.name == current }); found {
-		return true, p.processCommand(ctx, command)
+	if p.isTerminated {
+		return false, p.processTerminatedArg(p.command.onContext(ctx))
+	} else if current == terminator {
+		p.isTerminated = true
+		p.index += 1
+		return false, nil
+	} else if strings.HasPrefix(current, flagPrefix) {
+		return false, p.processFlag(p.command.onContext(ctx))
+	}
+
+	if command, found, err := p.command.findSubCommand(current); err != nil {
+		return false, err
+	} else if found {
+		return true, p.processCommand(ctx, p.useCommand(command))
 	}
 
-	return false, p.processArg()
+	if len(p.command.subCommands) > 0 && len(p.command.arguments) == 0 {
+		return false, errors.Wrapf(UnknownSubCommandError, "%q for %q%s", current, p.command.qualifiedName(), didYouMean(current, p.command.subCommandNames()))
+	}
+
+	return false, p.processArg(p.command.onContext(ctx))
 }
 
-func (p *parser) processFlag() error {
+func (p *parser) processFlag(ctx context.Context) error {
 	current, _ := p.current()
 
 	if strings.HasPrefix(current, longFlagPrefix) {
-		return p.processLongFlag()
+		return p.processLongFlag(ctx)
 	}
 
-	return p.processShortFlagGroup()
+	return p.processShortFlagGroup(ctx)
 }
 
-func (p *parser) processLongFlag() error {
+func (p *parser) processLongFlag(ctx context.Context) error {
 	current, _ := p.current()
 
 	if strings.Contains(current, "=") {
-		return p.processLongFlagWithEqual()
+		return p.processLongFlagWithEqual(ctx)
 	}
 
-	flag, found := p.command.findLongFlag(strings.TrimPrefix(current, longFlagPrefix))
+	flag, isNegated, found := p.command.findLongFlagOrNegation(strings.TrimPrefix(current, longFlagPrefix))
 	if !found {
-		return errors.Wrapf(InvalidFlagError, "no flag found for %q", current)
+		return errors.Wrapf(InvalidFlagError, "no flag found for %q%s", current, p.command.longFlagSuggestion(current))
 	}
 
-	if flag.isBool() {
-		flag.value = !flag.defaultValue.(bool)
+	flag = p.useFlag(flag, current)
+
+	if flag.isCounter {
+		flag.increment()
+		p.index += 1
+		return nil
+	} else if flag.isBool() {
+		if err := flag.setPresent(isNegated); err != nil {
+			return err
+		}
+
 		p.index += 1
 		return nil
 	}
@@ -95,44 +134,47 @@ func (p *parser) processLongFlag() error {
 		return errors.Wrapf(MissingFlagValueError, "flag %q", current)
 	}
 
-	value, err := flag.parser.Parse(next)
-	if err != nil {
+	if err := flag.setValue(ctx, next); err != nil {
 		return errors.Wrapf(err, "parsing provided value %q for flag %q", next, current)
 	}
 
-	flag.value = value
 	p.index += 2
 	return nil
 }
 
-func (p *parser) processLongFlagWithEqual() error {
+func (p *parser) processLongFlagWithEqual(ctx context.Context) error {
 	current, _ := p.current()
 
 	rawFlag, rawValue, _ := strings.Cut(current, "=")
-	flag, found := p.command.findLongFlag(strings.TrimPrefix(rawFlag, longFlagPrefix))
+	flag, isNegated, found := p.command.findLongFlagOrNegation(strings.TrimPrefix(rawFlag, longFlagPrefix))
 	if !found {
-		return errors.Wrapf(InvalidFlagError, "no flag found for %q", rawFlag)
+		return errors.Wrapf(InvalidFlagError, "no flag found for %q%s", rawFlag, p.command.longFlagSuggestion(rawFlag))
 	}
 
-	value, err := flag.parser.Parse(rawValue)
-	if err != nil {
+	flag = p.useFlag(flag, rawFlag)
+
+	setValue := flag.setValue
+	if isNegated {
+		setValue = flag.setNegatedValue
+	}
+
+	if err := setValue(ctx, rawValue); err != nil {
 		return errors.Wrapf(err, "parsing provided value %q for flag %q", rawValue, rawFlag)
 	}
 
-	flag.value = value
 	p.index += 1
 	return nil
 }
 
-func (p *parser) processShortFlagGroup() error {
+func (p *parser) processShortFlagGroup(ctx context.Context) error {
 	current, _ := p.current()
 	if strings.Contains(current, "=") {
-		return p.processShortFlagWithEqual()
+		return p.processShortFlagWithEqual(ctx)
 	}
 
 	incrementIndexBy := 1
 	for _, short := range strings.TrimPrefix(current, flagPrefix) {
-		wasValueProcessed, err := p.processShortFlag(short)
+		wasValueProcessed, err := p.processShortFlag(ctx, short)
 		if err != nil {
 			return err
 		}
@@ -146,15 +188,20 @@ func (p *parser) processShortFlagGroup() error {
 	return nil
 }
 
-func (p *parser) processShortFlag(short rune) (bool, error) {
+func (p *parser) processShortFlag(ctx context.Context, short rune) (bool, error) {
 	flag, found := p.command.findShortFlag(short)
 	if !found {
-		return false, errors.Wrapf(InvalidFlagError, "no short flag found for %q", dashifyShort(short))
+		current, _ := p.current()
+		return false, errors.Wrapf(InvalidFlagError, "no short flag found for %q%s", dashifyShort(short), p.command.shortFlagSuggestion(current, short))
 	}
 
-	if flag.isBool() {
-		flag.value = !flag.defaultValue.(bool)
+	flag = p.useFlag(flag, dashifyShort(short))
+
+	if flag.isCounter {
+		flag.increment()
 		return false, nil
+	} else if flag.isBool() {
+		return false, flag.setPresent(false)
 	}
 
 	next, nextPresent := p.next()
@@ -162,16 +209,14 @@ func (p *parser) processShortFlag(short rune) (bool, error) {
 		return false, errors.Wrapf(MissingFlagValueError, "flag %q", dashifyShort(short))
 	}
 
-	value, err := flag.parser.Parse(next)
-	if err != nil {
+	if err := flag.setValue(ctx, next); err != nil {
 		return false, errors.Wrapf(err, "parsing provided value %q for flag %q", next, dashifyShort(short))
 	}
 
-	flag.value = value
 	return true, nil
 }
 
-func (p *parser) processShortFlagWithEqual() error {
+func (p *parser) processShortFlagWithEqual(ctx context.Context) error {
 	current, _ := p.current()
 
 	rawFlag, rawValue, _ := strings.Cut(current, "=")
@@ -183,45 +228,158 @@ func (p *parser) processShortFlagWithEqual() error {
 	short := rune(flagName[0])
 	flag, found := p.command.findShortFlag(short)
 	if !found {
-		return errors.Wrapf(InvalidFlagError, "no short flag found for %q", dashifyShort(short))
+		return errors.Wrapf(InvalidFlagError, "no short flag found for %q%s", dashifyShort(short), p.command.shortFlagSuggestion(rawFlag, short))
 	}
 
-	value, err := flag.parser.Parse(rawValue)
-	if err != nil {
+	flag = p.useFlag(flag, dashifyShort(short))
+
+	if err := flag.setValue(ctx, rawValue); err != nil {
 		return errors.Wrapf(err, "parsing provided value %q for flag %q", rawValue, dashifyShort(short))
 	}
 
-	flag.value = value
 	p.index += 1
 	return nil
 }
 
 func (p *parser) processCommand(ctx context.Context, command *Command) error {
-	return command.Run(ctx, p.unprocessed())
+	return p.runSubCommand(command, ctx, p.unprocessed())
 }
 
-func (p *parser) processArg() error {
+func (p *parser) processArg(ctx context.Context) error {
 	if p.argumentIndex >= len(p.command.arguments) {
 		return errors.Wrapf(TooManyArgumentsError, "only expected %d arguments", len(p.command.arguments))
 	}
 
 	current, _ := p.current()
 	argument := p.command.arguments[p.argumentIndex]
-	value, err := argument.parser.Parse(current)
-	if err != nil {
+	if argument.isFull() {
+		return errors.Wrapf(TooManyArgumentsError, "argument %q accepts at most %d values", argument.name, argument.maxCount)
+	}
+
+	p.useArgument(argument)
+	if err := argument.setValue(ctx, current); err != nil {
 		return errors.Wrapf(err, "parsing provided value %q for argument %d", current, p.argumentIndex+1)
 	}
 
-	argument.value = value
 	p.index += 1
-	p.argumentIndex += 1
+	if !argument.isVariadic {
+		p.argumentIndex += 1
+	}
+
+	return nil
+}
+
+func (p *parser) processTerminatedArg(ctx context.Context) error {
+	if p.argumentIndex < len(p.command.arguments) && !p.command.arguments[p.argumentIndex].isFull() {
+		return p.processArg(ctx)
+	}
+
+	current, _ := p.current()
+	p.command.restArgs = append(p.command.restArgs, current)
+	p.index += 1
 	return nil
 }
 
+func (c *Command) findSubCommand(name string) (*Command, bool, error) {
+	if command, found := lo.Find(c.subCommands, func(subCommand *Command) bool { return subCommand.hasName(name) }); found {
+		return command, true, nil
+	}
+
+	if name == "" || !c.isPrefixMatchingEnabled() {
+		return nil, false, nil
+	}
+
+	candidates := lo.Filter(c.visibleSubCommands(), func(subCommand *Command, _ int) bool {
+		return lo.ContainsBy(subCommand.names(), func(subCommandName string) bool { return strings.HasPrefix(subCommandName, name) })
+	})
+
+	switch len(candidates) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		return candidates[0], true, nil
+	default:
+		names := lo.Map(candidates, func(candidate *Command, _ int) string { return fmt.Sprintf("%q", candidate.name) })
+		return nil, false, errors.Wrapf(AmbiguousSubCommandError, "%q could be %s", name, strings.Join(names, ", "))
+	}
+}
+
+func (c *Command) subCommandNames() []string {
+	return lo.FlatMap(c.visibleSubCommands(), func(subCommand *Command, _ int) []string { return subCommand.names() })
+}
+
+func (c *Command) dashedLongFlagNames() []string {
+	var names []string
+	for _, flag := range c.flagsUpToRoot() {
+		if !flag.isHidden {
+			names = append(names, lo.Map(flag.longNames(), func(name string, _ int) string { return longFlagPrefix + name })...)
+		}
+	}
+
+	return names
+}
+
+func (c *Command) dashedShortFlagNames() []string {
+	var names []string
+	for _, flag := range c.flagsUpToRoot() {
+		if !flag.isHidden {
+			names = append(names, lo.Map(flag.shorts, func(short rune, _ int) string { return dashifyShort(short) })...)
+		}
+	}
+
+	return names
+}
+
+// longFlagSuggestion returns a hint for an unknown long flag. A single letter is only matched against shorts exactly,
+// since any short is within edit distance of it.
+func (c *Command) longFlagSuggestion(rawFlag string) string {
+	if name := strings.TrimPrefix(rawFlag, longFlagPrefix); utf8.RuneCountInString(name) == 1 {
+		if shortFlag := flagPrefix + name; lo.Contains(c.dashedShortFlagNames(), shortFlag) {
+			return didYouMean(shortFlag, []string{shortFlag})
+		}
+	}
+
+	return didYouMean(rawFlag, c.dashedLongFlagNames())
+}
+
+// shortFlagSuggestion returns a hint for an unknown short flag. Edit distance says little about single letters, so it
+// suggests a long flag spelled out after a single dash, or else the same letter in the other case.
+func (c *Command) shortFlagSuggestion(rawFlag string, short rune) string {
+	if group := strings.TrimPrefix(rawFlag, flagPrefix); utf8.RuneCountInString(group) > 1 {
+		if hint := didYouMean(longFlagPrefix+group, c.dashedLongFlagNames()); hint != "" {
+			return hint
+		}
+	}
+
+	shortFlag := dashifyShort(short)
+	otherCase := lo.Filter(c.dashedShortFlagNames(), func(name string, _ int) bool {
+		return name != shortFlag && strings.EqualFold(name, shortFlag)
+	})
+
+	return didYouMean(shortFlag, otherCase)
+}
+
 func (c *Command) findLongFlag(name string) (*Flag, bool) {
 	return c.findFlagUpToRoot(func(flag *Flag) bool { return flag.name == name || lo.Contains(flag.aliases, name) })
 }
 
+func (c *Command) findLongFlagOrNegation(name string) (*Flag, bool, bool) {
+	if flag, found := c.findLongFlag(name); found {
+		return flag, false, true
+	}
+
+	negatedName, isNegation := strings.CutPrefix(name, negationPrefix)
+	if !isNegation {
+		return nil, false, false
+	}
+
+	flag, found := c.findFlagUpToRoot(func(flag *Flag) bool {
+		return flag.isNegatable && (flag.name == negatedName || lo.Contains(flag.aliases, negatedName))
+	})
+
+	return flag, true, found
+}
+
 func (c *Command) findShortFlag(short rune) (*Flag, bool) {
 	return c.findFlagUpToRoot(func(flag *Flag) bool { return lo.Contains(flag.shorts, short) })
 }
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/bobg/errors"
+	"github.com/samber/lo"
+)
+
+// SchemaVersion is the version of the Schema format. It is incremented when the format changes incompatibly.
+const SchemaVersion = 1
+
+const helpFormatJSON = "json"
+
+// Schema is a machine-readable description of a command tree.
+type Schema struct {
+	Version int           `json:"version"`
+	Command CommandSchema `json:"command"`
+}
+
+// CommandSchema describes a command, its flags, arguments and sub-commands.
+type CommandSchema struct {
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	LongDescription string           `json:"long_description,omitempty"`
+	Version         string           `json:"version,omitempty"`
+	Aliases         []string         `json:"aliases,omitempty"`
+	Group           string           `json:"group,omitempty"`
+	Hidden          bool             `json:"hidden"`
+	Deprecated      string           `json:"deprecated,omitempty"`
+	ReplacedBy      string           `json:"replaced_by,omitempty"`
+	Flags           []FlagSchema     `json:"flags,omitempty"`
+	GlobalFlags     []FlagSchema     `json:"global_flags,omitempty"`
+	Arguments       []ArgumentSchema `json:"arguments,omitempty"`
+	SubCommands     []CommandSchema  `json:"sub_commands,omitempty"`
+	Examples        []Example        `json:"examples,omitempty"`
+	Epilog          string           `json:"epilog,omitempty"`
+}
+
+// FlagSchema describes a flag.
+type FlagSchema struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Shorts      []string `json:"shorts,omitempty"`
+	Group       string   `json:"group,omitempty"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Env         string   `json:"env,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	Hidden      bool     `json:"hidden"`
+	Inherited   bool     `json:"inherited"`
+	Required    bool     `json:"required"`
+	Repeatable  bool     `json:"repeatable"`
+	Negatable   bool     `json:"negatable"`
+	Counter     bool     `json:"counter"`
+	Deprecated  string   `json:"deprecated,omitempty"`
+	ReplacedBy  string   `json:"replaced_by,omitempty"`
+	DefinedBy   string   `json:"defined_by,omitempty"`
+}
+
+// ArgumentSchema describes a positional argument.
+type ArgumentSchema struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Default     *string  `json:"default,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	Required    bool     `json:"required"`
+	Variadic    bool     `json:"variadic"`
+	MinCount    int      `json:"min_count,omitempty"`
+	MaxCount    int      `json:"max_count,omitempty"`
+	Deprecated  string   `json:"deprecated,omitempty"`
+}
+
+// Schema returns a description of the command and its sub-commands.
+func (c *Command) Schema() Schema {
+	return Schema{Version: SchemaVersion, Command: c.commandSchema(context.Background())}
+}
+
+func (c *Command) writeSchema(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c.Schema()); err != nil {
+		return errors.Wrap(err, "writing schema")
+	}
+
+	return nil
+}
+
+// commandSchema describes the command, calling ChoicesFuncs with ctx carrying the command they belong to.
+func (c *Command) commandSchema(ctx context.Context) CommandSchema {
+	commandCtx := c.onContext(ctx)
+	return CommandSchema{
+		Name:            c.name,
+		Description:     c.description,
+		LongDescription: c.longDescription,
+		Version:         c.version,
+		Aliases:         c.aliases,
+		Group:           c.group,
+		Hidden:          c.isHidden,
+		Deprecated:      c.deprecation,
+		ReplacedBy:      c.replacedBy,
+		Flags:           lo.Map(c.flags, func(flag *Flag, _ int) FlagSchema { return flag.schema(commandCtx) }),
+		GlobalFlags:     c.globalFlagSchemas(commandCtx),
+		Arguments:       lo.Map(c.arguments, func(argument *Argument, _ int) ArgumentSchema { return argument.schema(commandCtx) }),
+		SubCommands:     lo.Map(c.subCommands, func(command *Command, _ int) CommandSchema { return command.commandSchema(ctx) }),
+		Examples:        c.examples,
+		Epilog:          c.epilog,
+	}
+}
+
+// globalFlagSchemas describes the flags the command inherits from its ancestors, with the qualified name of the
+// ancestor which defined each one.
+func (c *Command) globalFlagSchemas(ctx context.Context) []FlagSchema {
+	return lo.FilterMap(c.flagsUpToRoot(), func(flag *Flag, _ int) (FlagSchema, bool) {
+		owner, found := c.findFlagOwner(flag)
+		if !found || owner == c {
+			return FlagSchema{}, false
+		}
+
+		schema := flag.schema(ctx)
+		schema.DefinedBy = owner.qualifiedName()
+		return schema, true
+	})
+}
+
+func (f *Flag) schema(ctx context.Context) FlagSchema {
+	return FlagSchema{
+		Name:        f.name,
+		Description: f.description,
+		Aliases:     f.aliases,
+		Shorts:      lo.Map(f.shorts, func(short rune, _ int) string { return string(short) }),
+		Group:       f.group,
+		Type:        fmt.Sprintf("%T", f.parser.Type()),
+		Default:     fmt.Sprint(f.defaultValue),
+		Env:         f.defaultEnvName,
+		Choices:     f.allowedChoices(ctx),
+		Hidden:      f.isHidden,
+		Inherited:   f.isInherited,
+		Required:    f.isRequired,
+		Repeatable:  f.isRepeatable(),
+		Negatable:   f.isNegatable,
+		Counter:     f.isCounter,
+		Deprecated:  f.deprecation,
+		ReplacedBy:  f.replacedBy,
+	}
+}
+
+func (a *Argument) schema(ctx context.Context) ArgumentSchema {
+	schema := ArgumentSchema{
+		Name:        a.name,
+		Description: a.description,
+		Type:        fmt.Sprintf("%T", a.parser.Type()),
+		Choices:     a.allowedChoices(ctx),
+		Required:    a.isRequired(),
+		Variadic:    a.isVariadic,
+		MinCount:    a.minCount,
+		MaxCount:    a.maxCount,
+		Deprecated:  a.deprecation,
+	}
+
+	if a.defaultValue != nil {
+		schema.Default = lo.ToPtr(fmt.Sprint(a.defaultValue))
+	}
+
+	return schema
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Schema(t *testing.T) {
+	command, err := NewCommand("server", "An http server.",
+		SetVersion("v0.1.0"),
+		AddHelpFlag(AddFlagShort('h'), SetFlagIsInherited(true)),
+		AddFlag("port", "Port", AddFlagShort('p'), SetFlagDefault(3000), SetFlagDefaultEnv("PORT")),
+		AddSubCmd("proxy", "Proxy requests",
+			AddAlias("p"),
+			AddFlag("token", "Token", SetFlagRequired(true), SetFlagIsHidden(true)),
+			AddArg("target", "Target", SetArgChoices("a", "b"), SetArgChoicesFunc(func(context.Context) []string { return []string{"c"} })),
+			AddArg("paths", "Paths", SetArgVariadicCount(0, 3)),
+		),
+	)
+
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, command.writeSchema(buffer))
+
+	assert.JSONEq(t,
+		heredoc.Doc(`
+			{
+				"version": 1,
+				"command": {
+					"name": "server",
+					"description": "An http server.",
+					"version": "v0.1.0",
+					"hidden": false,
+					"flags": [
+						{"name": "help", "description": "Print help.", "shorts": ["h"], "type": "bool", "default": "false", "hidden": false, "inherited": true, "required": false, "repeatable": false, "negatable": false, "counter": false},
+						{"name": "port", "description": "Port", "shorts": ["p"], "type": "int", "default": "3000", "env": "PORT", "hidden": false, "inherited": false, "required": false, "repeatable": false, "negatable": false, "counter": false}
+					],
+					"sub_commands": [
+						{
+							"name": "proxy",
+							"description": "Proxy requests",
+							"aliases": ["p"],
+							"hidden": false,
+							"flags": [
+								{"name": "token", "description": "Token", "type": "string", "default": "", "hidden": true, "inherited": false, "required": true, "repeatable": false, "negatable": false, "counter": false}
+							],
+							"global_flags": [
+								{"name": "help", "description": "Print help.", "shorts": ["h"], "type": "bool", "default": "false", "hidden": false, "inherited": true, "required": false, "repeatable": false, "negatable": false, "counter": false, "defined_by": "server"}
+							],
+							"arguments": [
+								{"name": "target", "description": "Target", "type": "string", "choices": ["a", "b", "c"], "required": true, "variadic": false},
+								{"name": "paths", "description": "Paths", "type": "[]string", "required": false, "variadic": true, "max_count": 3}
+							]
+						}
+					]
+				}
+			}
+		`),
+		buffer.String(),
+	)
+}
+
+func TestCommand_Run_jsonHelp(t *testing.T) {
+	command, err := NewCommand("server", "An http server.",
+		AddHelpFlag(SetFlagIsInherited(true)),
+		AddSubCmd("proxy", "Proxy requests"),
+	)
+
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = writer
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	require.NoError(t, command.Run(context.TODO(), []string{"proxy", "--help=json"}))
+	require.NoError(t, writer.Close())
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var schema Schema
+	require.NoError(t, json.Unmarshal(output, &schema))
+	assert.Equal(t, SchemaVersion, schema.Version)
+	assert.Equal(t, "proxy", schema.Command.Name)
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bobg/errors"
+	"github.com/broothie/option"
+)
+
+const (
+	structTagHelp      = "help"
+	structTagShort     = "short"
+	structTagDefault   = "default"
+	structTagEnv       = "env"
+	structTagHidden    = "hidden"
+	structTagInherited = "inherited"
+	structTagArg       = "arg"
+
+	subCommandMethodPrefix = "Cmd"
+	subCommandHelpSuffix   = "Help"
+)
+
+var InvalidStructCommandError = errors.New("invalid struct command")
+
+// Runner is implemented by structs passed to FromStruct which handle their command. Run is called on a fresh value
+// with its fields bound to the parsed flags and arguments.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// FromStruct adds flags, arguments and sub-commands to the command from the fields of struct T tagged `cli:"name"`.
+//
+// A field becomes a flag, or an argument when tagged `arg:"true"`, whose type is that of the field, which must be
+// Parseable. A slice argument is variadic. Further tags set its description (`help`), short flag (`short`), default
+// value (`default`), environment variable (`env`), and whether it is hidden (`hidden`) or inherited (`inherited`).
+// A field of struct type becomes a sub-command built from that struct, described by its `help` tag.
+//
+// A method of *T named Cmd<Name> with the signature func(context.Context) error becomes a sub-command named <name> in
+// kebab case, run on a fresh *T bound to the command's flags. It is described by the string returned by the method
+// Cmd<Name>Help, if there is one.
+//
+// If *T implements Runner, it becomes the command's handler.
+func FromStruct[T any]() option.Func[*Command] {
+	return fromStructType(reflect.TypeFor[T]())
+}
+
+func fromStructType(structType reflect.Type) option.Func[*Command] {
+	return func(command *Command) (*Command, error) {
+		if structType.Kind() != reflect.Struct {
+			return nil, errors.Wrapf(InvalidStructCommandError, "type %s is not a struct", structType)
+		}
+
+		var options []option.Option[*Command]
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			name, found := field.Tag.Lookup(bindTag)
+			if !found || name == "" || name == "-" {
+				continue
+			}
+
+			fieldOption, err := structFieldOption(name, field)
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %s", field.Name)
+			}
+
+			options = append(options, fieldOption)
+		}
+
+		options = append(options, structMethodOptions(structType)...)
+		if reflect.PointerTo(structType).Implements(reflect.TypeFor[Runner]()) {
+			options = append(options, SetHandler(structHandler(structType)))
+		}
+
+		return option.Apply(command, options...)
+	}
+}
+
+func structMethodOptions(structType reflect.Type) []option.Option[*Command] {
+	pointerType := reflect.PointerTo(structType)
+	handlerType := reflect.FuncOf([]reflect.Type{pointerType, reflect.TypeFor[context.Context]()}, []reflect.Type{reflect.TypeFor[error]()}, false)
+	helpType := reflect.FuncOf([]reflect.Type{pointerType}, []reflect.Type{reflect.TypeFor[string]()}, false)
+
+	var options []option.Option[*Command]
+	for i := 0; i < pointerType.NumMethod(); i++ {
+		method := pointerType.Method(i)
+		name, isSubCommand := strings.CutPrefix(method.Name, subCommandMethodPrefix)
+		if !isSubCommand || name == "" || method.Type != handlerType {
+			continue
+		}
+
+		var description string
+		if helpMethod, found := pointerType.MethodByName(method.Name + subCommandHelpSuffix); found && helpMethod.Type == helpType {
+			description = helpMethod.Func.Call([]reflect.Value{reflect.New(structType)})[0].String()
+		}
+
+		options = append(options, AddSubCmd(kebabCase(name), description, SetHandler(structMethodHandler(structType, method))))
+	}
+
+	return options
+}
+
+func structFieldOption(name string, field reflect.StructField) (option.Option[*Command], error) {
+	description := field.Tag.Get(structTagHelp)
+	if isSubCommandType(field.Type) {
+		return AddSubCmd(name, description, fromStructType(field.Type)), nil
+	}
+
+	if !field.IsExported() {
+		return nil, errors.Wrap(InvalidStructCommandError, "field is not exported")
+	}
+
+	isArg, err := structTagBool(field, structTagArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if isArg {
+		argumentOptions, err := structArgumentOptions(field)
+		if err != nil {
+			return nil, err
+		}
+
+		return AddArg(name, description, argumentOptions...), nil
+	}
+
+	flagOptions, err := structFlagOptions(field)
+	if err != nil {
+		return nil, err
+	}
+
+	return AddFlag(name, description, flagOptions...), nil
+}
+
+func structFlagOptions(field reflect.StructField) ([]option.Option[*Flag], error) {
+	parser, err := argParserForValue(reflect.Zero(field.Type).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	var defaultValue any
+	if rawDefault, found := field.Tag.Lookup(structTagDefault); found {
+		if defaultValue, err = parser.Parse(rawDefault); err != nil {
+			return nil, errors.Wrapf(err, "parsing default value %q", rawDefault)
+		}
+	} else if _, isAccumulating := parser.(accumulatingArgParser); isAccumulating {
+		defaultValue, _ = parser.Parse("")
+	} else {
+		defaultValue = reflect.Zero(field.Type).Interface()
+	}
+
+	options := []option.Option[*Flag]{setFlagParserAndDefault(parser, defaultValue)}
+	if short, found := field.Tag.Lookup(structTagShort); found {
+		if utf8.RuneCountInString(short) != 1 {
+			return nil, errors.Wrapf(InvalidStructCommandError, "short flag %q must be a single character", short)
+		}
+
+		shortRune, _ := utf8.DecodeRuneInString(short)
+		options = append(options, AddFlagShort(shortRune))
+	}
+
+	if env, found := field.Tag.Lookup(structTagEnv); found {
+		options = append(options, SetFlagDefaultEnv(env))
+	}
+
+	isHidden, err := structTagBool(field, structTagHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	isInherited, err := structTagBool(field, structTagInherited)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(options, SetFlagIsHidden(isHidden), SetFlagIsInherited(isInherited)), nil
+}
+
+func structArgumentOptions(field reflect.StructField) ([]option.Option[*Argument], error) {
+	isVariadic := field.Type.Kind() == reflect.Slice
+	elementType := field.Type
+	if isVariadic {
+		elementType = field.Type.Elem()
+	}
+
+	parser, err := argParserForValue(reflect.Zero(elementType).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	options := []option.Option[*Argument]{setArgParser(parser)}
+	if isVariadic {
+		options = append(options, SetArgVariadic())
+	}
+
+	if rawDefault, found := field.Tag.Lookup(structTagDefault); found {
+		defaultParser, err := argParserForValue(reflect.Zero(field.Type).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		defaultValue, err := defaultParser.Parse(rawDefault)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing default value %q", rawDefault)
+		}
+
+		options = append(options, setArgDefaultValue(defaultValue))
+	}
+
+	return options, nil
+}
+
+func structHandler(structType reflect.Type) Handler {
+	return func(ctx context.Context) error {
+		command, err := commandFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		target := reflect.New(structType)
+		if err := command.bind(ctx, target.Elem()); err != nil {
+			return err
+		}
+
+		return target.Interface().(Runner).Run(ctx)
+	}
+}
+
+// structMethodHandler calls the method on a fresh struct bound to the flags of the command built from the struct, which
+// is the parent of the method's sub-command.
+func structMethodHandler(structType reflect.Type, method reflect.Method) Handler {
+	return func(ctx context.Context) error {
+		command, err := commandFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		target := reflect.New(structType)
+		if err := command.parent.bind(ctx, target.Elem()); err != nil {
+			return err
+		}
+
+		err, _ = method.Func.Call([]reflect.Value{target, reflect.ValueOf(ctx)})[0].Interface().(error)
+		return err
+	}
+}
+
+func structTagBool(field reflect.StructField, key string) (bool, error) {
+	rawValue, found := field.Tag.Lookup(key)
+	if !found {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(rawValue)
+	if err != nil {
+		return false, errors.Wrapf(InvalidStructCommandError, "tag %s: %q is not a bool", key, rawValue)
+	}
+
+	return value, nil
+}
+
+// kebabCase converts a Go identifier such as ListAll or HTTPServe to list-all or http-serve.
+func kebabCase(name string) string {
+	runes := []rune(name)
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			builder.WriteRune('-')
+		}
+
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
+
+func isSubCommandType(fieldType reflect.Type) bool {
+	return fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeFor[time.Time]()
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	Port    int       `cli:"port" help:"Port to run on" short:"p" default:"3000" env:"TEST_STRUCT_PORT" inherited:"true"`
+	Debug   bool      `cli:"debug" help:"Enable debugging" hidden:"true"`
+	Tags    []string  `cli:"tag" help:"Tags"`
+	Proxy   testProxy `cli:"proxy" help:"Proxy requests"`
+	Ignored string
+}
+
+type testProxy struct {
+	Timeout time.Duration `cli:"timeout" help:"Timeout" default:"1s"`
+	Target  string        `cli:"target" help:"Target to proxy to" arg:"true"`
+	Paths   []string      `cli:"paths" help:"Paths to proxy" arg:"true" default:"/"`
+}
+
+var testProxyRuns []testProxy
+
+func (p testProxy) Run(context.Context) error {
+	testProxyRuns = append(testProxyRuns, p)
+	return nil
+}
+
+var testServerRouteListings []testServer
+
+func (s *testServer) CmdListRoutes(context.Context) error {
+	testServerRouteListings = append(testServerRouteListings, *s)
+	return nil
+}
+
+func (*testServer) CmdListRoutesHelp() string {
+	return "List routes"
+}
+
+func TestFromStruct(t *testing.T) {
+	command, err := NewCommand("server", "An http server.",
+		AddHelpFlag(SetFlagIsInherited(true)),
+		FromStruct[testServer](),
+	)
+
+	require.NoError(t, err)
+
+	t.Run("help", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		require.NoError(t, command.renderHelp(buffer))
+
+		assert.Equal(t,
+			heredoc.Doc(`
+				server: An http server.
+
+				Usage:
+				  server [flags] [sub-command]
+
+				Sub-commands:
+				  proxy: Proxy requests
+				  list-routes: List routes
+
+				Flags:
+				  --help      Print help.     (type: bool, default: "false")
+				  --port  -p  Port to run on  (type: int, default: $TEST_STRUCT_PORT, "3000")
+				  --tag       Tags            (type: []string, default: "[]", repeatable)
+
+			`),
+			buffer.String(),
+		)
+	})
+
+	t.Run("no handler without Run", func(t *testing.T) {
+		assert.Nil(t, command.handler)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		testProxyRuns = nil
+		t.Setenv("TEST_STRUCT_PORT", "8080")
+
+		require.NoError(t, command.Run(context.TODO(), []string{"proxy", "--timeout", "5s", "example.com", "/a", "/b"}))
+		assert.Equal(t, []testProxy{{Timeout: 5 * time.Second, Target: "example.com", Paths: []string{"/a", "/b"}}}, testProxyRuns)
+
+		port, err := FlagValue[int](command.subCommands[0].onContext(context.TODO()), "port")
+		require.NoError(t, err)
+		assert.Equal(t, 8080, port)
+	})
+
+	t.Run("method sub-command", func(t *testing.T) {
+		testServerRouteListings = nil
+		require.NoError(t, command.Run(context.TODO(), []string{"--debug", "--port", "9000", "list-routes"}))
+		require.Len(t, testServerRouteListings, 1)
+		assert.Equal(t, 9000, testServerRouteListings[0].Port)
+		assert.True(t, testServerRouteListings[0].Debug)
+	})
+
+	t.Run("argument default", func(t *testing.T) {
+		testProxyRuns = nil
+		command, err := NewCommand("server", "An http server.", FromStruct[testServer]())
+		require.NoError(t, err)
+
+		require.NoError(t, command.Run(context.TODO(), []string{"proxy", "example.com"}))
+		assert.Equal(t, []testProxy{{Timeout: time.Second, Target: "example.com", Paths: []string{"/"}}}, testProxyRuns)
+	})
+}
+
+func TestFromStruct_errors(t *testing.T) {
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := NewCommand("test", "test", FromStruct[string]())
+		assert.EqualError(t, err, `building command "test": failed to apply option 0: type string is not a struct: invalid struct command`)
+	})
+
+	t.Run("not parseable", func(t *testing.T) {
+		_, err := NewCommand("test", "test", FromStruct[struct {
+			Count int64 `cli:"count"`
+		}]())
+
+		assert.EqualError(t, err, `building command "test": failed to apply option 0: field Count: type int64: type not parseable`)
+	})
+
+	t.Run("invalid short", func(t *testing.T) {
+		_, err := NewCommand("test", "test", FromStruct[struct {
+			Verbose bool `cli:"verbose" short:"vv"`
+		}]())
+
+		assert.EqualError(t, err, `building command "test": failed to apply option 0: field Verbose: short flag "vv" must be a single character: invalid struct command`)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		_, err := NewCommand("test", "test", FromStruct[struct {
+			Port int `cli:"port" default:"http"`
+		}]())
+
+		assert.ErrorContains(t, err, `field Port: parsing default value "http"`)
+	})
+}
+
+func Test_kebabCase(t *testing.T) {
+	assert.Equal(t, "list", kebabCase("List"))
+	assert.Equal(t, "list-all", kebabCase("ListAll"))
+	assert.Equal(t, "http-serve", kebabCase("HTTPServe"))
+	assert.Equal(t, "v2-api", kebabCase("V2Api"))
+}
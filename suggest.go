@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+const maxSuggestionDistance = 2
+
+// suggestion returns the candidate closest to target, if any is close enough to be worth suggesting.
+func suggestion(target string, candidates []string) (string, bool) {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		distance := levenshteinDistance(target, candidate)
+		if target != "" && strings.HasPrefix(candidate, target) {
+			distance = min(distance, maxSuggestionDistance)
+		}
+
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best, bestDistance <= maxSuggestionDistance
+}
+
+// didYouMean returns a hint naming the candidate closest to target, or an empty string if none is close enough.
+func didYouMean(target string, candidates []string) string {
+	if closest, found := suggestion(target, candidates); found {
+		return fmt.Sprintf(" (did you mean %q?)", closest)
+	}
+
+	return ""
+}
+
+func levenshteinDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current := make([]int, len(target)+1)
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(target)]
+}
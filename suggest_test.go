@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_suggestion(t *testing.T) {
+	candidates := []string{"json", "yaml", "table"}
+
+	testCases := map[string]struct {
+		target            string
+		expectedCandidate string
+		expectedFound     bool
+	}{
+		"typo":          {target: "jsn", expectedCandidate: "json", expectedFound: true},
+		"transposition": {target: "tabel", expectedCandidate: "table", expectedFound: true},
+		"prefix":        {target: "ya", expectedCandidate: "yaml", expectedFound: true},
+		"too far":       {target: "protobuf"},
+		"empty":         {target: ""},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			candidate, found := suggestion(testCase.target, candidates)
+			assert.Equal(t, testCase.expectedCandidate, candidate)
+			assert.Equal(t, testCase.expectedFound, found)
+		})
+	}
+}
+
+func Test_levenshteinDistance(t *testing.T) {
+	assert.Equal(t, 0, levenshteinDistance("json", "json"))
+	assert.Equal(t, 1, levenshteinDistance("jsn", "json"))
+	assert.Equal(t, 3, levenshteinDistance("", "abc"))
+	assert.Equal(t, 3, levenshteinDistance("kitten", "sitting"))
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/samber/lo"
+	"golang.org/x/term"
+	"golang.org/x/text/width"
+)
+
+const (
+	columnsEnv        = "COLUMNS"
+	minWrapColumnSize = 20
+)
+
+// findHelpWidth returns the width help written to w is wrapped to: the nearest SetHelpWidth up the tree, then, if w is
+// a terminal, $COLUMNS or its width. Zero means help isn't wrapped, which is the default when w isn't a terminal so
+// that piped and captured help is stable.
+func (c *Command) findHelpWidth(w io.Writer) int {
+	for current := c; current != nil; current = current.parent {
+		if current.helpWidth != nil {
+			return *current.helpWidth
+		}
+	}
+
+	if !isTerminal(w) {
+		return 0
+	}
+
+	return terminalHelpWidth(w.(*os.File))
+}
+
+// terminalHelpWidth returns $COLUMNS, or else the width of the terminal on file.
+func terminalHelpWidth(file *os.File) int {
+	if columns, err := strconv.Atoi(os.Getenv(columnsEnv)); err == nil && columns > 0 {
+		return columns
+	}
+
+	columns, _, err := term.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return columns
+}
+
+// displayWidth is the number of terminal cells s takes up, ignoring ANSI escape codes.
+func displayWidth(s string) int {
+	total := 0
+	for _, r := range stripANSI(s) {
+		total += runeWidth(r)
+	}
+
+	return total
+}
+
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || unicode.IsControl(r) {
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// wrapText breaks text into lines no wider than limit, breaking only at spaces. Words wider than limit get a line of their own.
+func wrapText(text string, limit int) []string {
+	var (
+		lines     []string
+		line      []string
+		lineWidth int
+	)
+
+	for _, word := range strings.Fields(text) {
+		wordWidth := displayWidth(word)
+		if len(line) > 0 && lineWidth+1+wordWidth > limit {
+			lines = append(lines, strings.Join(line, " "))
+			line, lineWidth = nil, 0
+		}
+
+		if len(line) > 0 {
+			lineWidth += 1
+		}
+
+		line = append(line, word)
+		lineWidth += wordWidth
+	}
+
+	if len(line) > 0 {
+		lines = append(lines, strings.Join(line, " "))
+	}
+
+	return lo.Ternary(len(lines) == 0, []string{""}, lines)
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_displayWidth(t *testing.T) {
+	testCases := map[string]struct {
+		text          string
+		expectedWidth int
+	}{
+		"ascii":     {text: "--output", expectedWidth: 8},
+		"wide":      {text: "日本語", expectedWidth: 6},
+		"fullwidth": {text: "ＡＢ", expectedWidth: 4},
+		"combining": {text: "café", expectedWidth: 4},
+		"styled":    {text: Style("1;36").Render("--output"), expectedWidth: 8},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expectedWidth, displayWidth(testCase.text))
+		})
+	}
+}
+
+func Test_wrapText(t *testing.T) {
+	assert.Equal(t, []string{"one two", "three", "four"}, wrapText("one two three four", 8))
+	assert.Equal(t, []string{"a", "overlong", "b"}, wrapText("a overlong b", 4))
+	assert.Equal(t, []string{"日本", "語"}, wrapText("日本 語", 5))
+	assert.Equal(t, []string{""}, wrapText("", 10))
+}
+
+func TestCommand_findHelpWidth(t *testing.T) {
+	t.Run("not a terminal", func(t *testing.T) {
+		t.Setenv(columnsEnv, "42")
+
+		command, err := NewCommand("test", "test command", AddSubCmd("sub", "sub-command"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, command.subCommands[0].findHelpWidth(new(bytes.Buffer)))
+	})
+
+	t.Run("option", func(t *testing.T) {
+		command, err := NewCommand("test", "test command", SetHelpWidth(100), AddSubCmd("sub", "sub-command"))
+		require.NoError(t, err)
+		assert.Equal(t, 100, command.subCommands[0].findHelpWidth(new(bytes.Buffer)))
+	})
+
+	t.Run("negative option", func(t *testing.T) {
+		_, err := NewCommand("test", "test command", SetHelpWidth(-1))
+		assert.EqualError(t, err, `building command "test": failed to apply option 0: help width -1 cannot be negative`)
+	})
+}
+
+func Test_terminalHelpWidth(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+
+	t.Setenv(columnsEnv, "42")
+	assert.Equal(t, 42, terminalHelpWidth(file))
+
+	t.Setenv(columnsEnv, "wide")
+	assert.Equal(t, 0, terminalHelpWidth(file))
+}
+
+func TestCommand_renderHelp_wrapping(t *testing.T) {
+	command, err := NewCommand("test", "test command",
+		SetHelpWidth(60),
+		AddFlag("output", "Where the rendered report is written once every section has been collected", AddFlagShort('o'), SetFlagDefault("report.txt")),
+		AddFlag("日本語", "全角の説明", SetFlagDefault(false)),
+		AddArg("target", "The target which is inspected"),
+	)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, command.renderHelp(buffer))
+	assert.Equal(t, heredoc.Doc(`
+		test: test command
+
+		Usage:
+		  test [flags] <target>
+
+		Arguments:
+		  <target>  The target which is inspected  (type: string)
+
+		Flags:
+		  --output  -o  Where the rendered report is written once
+		                every section has been collected (type:
+		                string, default: "report.txt")
+		  --日本語      全角の説明 (type: bool, default: "false")
+
+	`), buffer.String())
+}
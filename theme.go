@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+const (
+	colorFlagName = "color"
+	noColorEnv    = "NO_COLOR"
+)
+
+// Color modes accepted by the flag added with AddColorFlag.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Style is a set of ANSI SGR parameters, e.g. "1;36" for bold cyan.
+type Style string
+
+// Render wraps s in the style's escape codes.
+func (s Style) Render(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", string(s), text)
+}
+
+// Theme is the set of styles used to render help, and available to handlers through ActiveTheme.
+type Theme struct {
+	Heading     Style
+	Flag        Style
+	Placeholder Style
+	Default     Style
+}
+
+// DefaultTheme is the theme used by commands which don't set one with SetTheme.
+var DefaultTheme = Theme{
+	Heading:     "1",
+	Flag:        "36",
+	Placeholder: "33",
+	Default:     "2",
+}
+
+// ActiveTheme returns the theme of the command in ctx for output written to stdout, or an unstyled Theme if color is
+// disabled.
+func ActiveTheme(ctx context.Context) Theme {
+	command, err := commandFromContext(ctx)
+	if err != nil {
+		return Theme{}
+	}
+
+	return command.activeTheme(os.Stdout)
+}
+
+// activeTheme returns the theme for output written to w.
+func (c *Command) activeTheme(w io.Writer) Theme {
+	if !c.isColorEnabled(w) {
+		return Theme{}
+	}
+
+	for current := c; current != nil; current = current.parent {
+		if current.theme != nil {
+			return *current.theme
+		}
+	}
+
+	return DefaultTheme
+}
+
+// isColorEnabled reports whether output written to w is styled. In auto mode, it is only styled if w is a terminal.
+func (c *Command) isColorEnabled(w io.Writer) bool {
+	colorMode := ColorAuto
+	if flag, found := c.findFlagUpToRoot(func(flag *Flag) bool { return flag.isColor }); found {
+		if value, err := c.flagValue(context.Background(), flag); err == nil {
+			colorMode = fmt.Sprint(value)
+		}
+	}
+
+	switch colorMode {
+	case ColorAlways:
+		return true
+
+	case ColorNever:
+		return false
+
+	default:
+		return os.Getenv(noColorEnv) == "" && isTerminal(w)
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	file, isFile := w.(*os.File)
+	if !isFile {
+		return false
+	}
+
+	fileInfo, err := file.Stat()
+	return err == nil && fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
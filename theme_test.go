@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyle_Render(t *testing.T) {
+	assert.Equal(t, "\x1b[1;36mflag\x1b[0m", Style("1;36").Render("flag"))
+	assert.Equal(t, "flag", Style("").Render("flag"))
+	assert.Equal(t, "", Style("1").Render(""))
+}
+
+func TestCommand_isColorEnabled(t *testing.T) {
+	testCases := map[string]struct {
+		rawArgs       []string
+		noColor       string
+		expectedColor bool
+	}{
+		"auto without terminal": {expectedColor: false},
+		"always":                {rawArgs: []string{"--color", "always"}, expectedColor: true},
+		"always beats NO_COLOR": {rawArgs: []string{"--color=always"}, noColor: "1", expectedColor: true},
+		"never":                 {rawArgs: []string{"--color", "never"}, expectedColor: false},
+		"inherited":             {rawArgs: []string{"sub", "--color", "always"}, expectedColor: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(noColorEnv, testCase.noColor)
+
+			handler := func(ctx context.Context) error {
+				assert.Equal(t, testCase.expectedColor, ActiveTheme(ctx) != Theme{})
+				return nil
+			}
+
+			command, err := NewCommand("test", "test command",
+				AddColorFlag(),
+				SetHandler(handler),
+				AddSubCmd("sub", "sub-command", SetHandler(handler)),
+			)
+
+			require.NoError(t, err)
+			require.NoError(t, command.Run(context.TODO(), testCase.rawArgs))
+		})
+	}
+
+	t.Run("invalid mode", func(t *testing.T) {
+		command, err := NewCommand("test", "test command", AddColorFlag())
+		require.NoError(t, err)
+
+		err = command.Run(context.TODO(), []string{"--color", "sometimes"})
+		assert.ErrorIs(t, err, InvalidChoiceError)
+	})
+}
+
+func Test_isTerminal(t *testing.T) {
+	assert.False(t, isTerminal(new(bytes.Buffer)))
+
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	assert.False(t, isTerminal(file))
+}
+
+func TestCommand_renderHelp_theme(t *testing.T) {
+	theme := Theme{Heading: "1", Flag: "36", Placeholder: "33", Default: "2"}
+
+	newCommand := func(t *testing.T, rawArgs ...string) *Command {
+		command, err := NewCommand("test", "test command",
+			AddColorFlag(),
+			SetTheme(theme),
+			AddSubCmd("sub", "sub-command",
+				AddFlag("port", "port", AddFlagShort('p'), SetFlagDefault(3000)),
+				AddArg("target", "target"),
+			),
+		)
+
+		require.NoError(t, err)
+		_, err = command.newParser(rawArgs).parse(context.TODO())
+		require.NoError(t, err)
+		return command.subCommands[0]
+	}
+
+	plain := new(bytes.Buffer)
+	require.NoError(t, newCommand(t, "--color=never").renderHelp(plain))
+	assert.NotContains(t, plain.String(), "\x1b[")
+
+	styled := new(bytes.Buffer)
+	require.NoError(t, newCommand(t, "--color=always").renderHelp(styled))
+
+	output := styled.String()
+	assert.Contains(t, output, theme.Heading.Render("Flags:"))
+	assert.Contains(t, output, theme.Flag.Render("--port"))
+	assert.Contains(t, output, theme.Flag.Render("-p"))
+	assert.Contains(t, output, theme.Placeholder.Render("<target>"))
+	assert.Contains(t, output, theme.Default.Render(`"3000"`))
+	assert.Equal(t, plain.String(), stripANSI(output))
+}
+
+func TestActiveTheme(t *testing.T) {
+	assert.Equal(t, Theme{}, ActiveTheme(context.TODO()))
+}